@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"sync"
 
 	"go.uber.org/automaxprocs/maxprocs"
@@ -32,13 +33,30 @@ type App[C tcfg.Config] interface {
 	Close(ctx context.Context) error
 }
 
-var _ App[tcfg.Config] = (*BaseApp[tcfg.Config])(nil)
+// Reporting is implemented by the apps sending error reports, the command flushes them on shutdown.
+type Reporting interface {
+	R() Reporter
+}
+
+// LogCloser is implemented by the apps owning the outputs of their logger.
+// The command calls CloseLog once the shutdown is over and its last entries are synced.
+type LogCloser interface {
+	CloseLog() error
+}
+
+var (
+	_ App[tcfg.Config] = (*BaseApp[tcfg.Config])(nil)
+	_ LogCloser        = (*BaseApp[tcfg.Config])(nil)
+	_ Reporting        = (*BaseApp[tcfg.Config])(nil)
+)
 
 type BaseApp[C tcfg.Config] struct {
 	tdep.Container
 
-	cfg C
-	log *zap.Logger
+	cfg      C
+	log      *zap.Logger
+	closeLog func() error
+	reporter *switchReporter
 
 	closed   bool
 	closers  []CloseFunc
@@ -47,7 +65,7 @@ type BaseApp[C tcfg.Config] struct {
 
 func NewBaseApp[C tcfg.Config](configLoader *tcfg.Loader[C]) (*BaseApp[C], error) {
 	log := zap.New(tzap.DefaultStdCoreConfig(zap.InfoLevel).Console())
-	defer zap.ReplaceGlobals(log)
+	defer func() { zap.ReplaceGlobals(log) }()
 
 	config, err := configLoader.Get()
 	if err != nil {
@@ -59,19 +77,28 @@ func NewBaseApp[C tcfg.Config](configLoader *tcfg.Loader[C]) (*BaseApp[C], error
 		return nil, fmt.Errorf("parse log level: %w", err)
 	}
 
-	var (
-		appEnv  = config.AppEnv()
-		zapCfg  = tzap.DefaultStdCoreConfig(logLevel)
-		zapCore zapcore.Core
-	)
+	reportLevel, err := zapcore.ParseLevel(config.ReportConfig().Level)
+	if err != nil {
+		return nil, fmt.Errorf("parse report level: %w", err)
+	}
+
+	reporter, err := newReporter(config)
+	if err != nil {
+		return nil, fmt.Errorf("new reporter: %w", err)
+	}
 
-	if appEnv == tcfg.EnvDev {
-		zapCore = zapCfg.Console()
-	} else {
-		zapCore = zapCfg.JSON()
+	// the reports are deduplicated, rate limited and sampled along with the log
+	appLog, closeLog, err := tzap.New(config.LogConfig(), logLevel, newReportCore(reporter, reportLevel))
+	if err != nil {
+		if sentry, ok := reporter.Current().(*SentryReporter); ok {
+			_ = sentry.Close(context.Background()) // nothing reported yet
+		}
+
+		return nil, fmt.Errorf("new logger: %w", err)
 	}
 
-	log = zap.New(zapCore).Named(config.AppName()).With(zap.String("env", appEnv.String()))
+	log = appLog.Named(config.AppName()).With(zap.String("env", config.AppEnv().String()))
+	slog.SetDefault(slog.New(tzap.NewSlogHandler(log.Named("slog"))))
 
 	_, err = maxprocs.Set(
 		maxprocs.Logger(
@@ -79,17 +106,57 @@ func NewBaseApp[C tcfg.Config](configLoader *tcfg.Loader[C]) (*BaseApp[C], error
 		),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("set maxprocs: %w", err)
+		return nil, errors.Join(fmt.Errorf("set maxprocs: %w", err), closeLog())
+	}
+
+	app := &BaseApp[C]{
+		cfg:      config,
+		log:      log,
+		closeLog: closeLog,
+		reporter: reporter,
+	}
+
+	if sentry, ok := reporter.Current().(*SentryReporter); ok {
+		app.AddCloser(sentry.Close)
+	}
+
+	return app, nil
+}
+
+// newReporter builds a [SentryReporter] if the DSN is configured.
+func newReporter(config tcfg.Config) (*switchReporter, error) {
+	reporter := new(switchReporter)
+
+	rc := config.ReportConfig()
+	if rc.DSN == "" {
+		return reporter, nil
+	}
+
+	sentry, err := NewSentryReporter(SentryConfig{
+		DSN:           rc.DSN,
+		App:           config.AppName(),
+		Env:           config.AppEnv().String(),
+		Version:       config.AppVersion(),
+		BatchSize:     rc.BatchSize,
+		FlushInterval: rc.FlushIntervalSeconds(),
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &BaseApp[C]{
-		cfg: config,
-		log: log,
-	}, nil
+	reporter.Set(sentry)
+
+	return reporter, nil
 }
 
 func (a *BaseApp[C]) C() C           { return a.cfg }
 func (a *BaseApp[C]) L() *zap.Logger { return a.log }
+func (a *BaseApp[C]) R() Reporter    { return a.reporter }
+
+// SetReporter replaces the reporter receiving the app log errors, recovered panics and failed shutdowns.
+func (a *BaseApp[C]) SetReporter(r Reporter) {
+	a.reporter.Set(r)
+}
 
 func (a *BaseApp[C]) AddCloser(fns ...CloseFunc) {
 	a.closerMu.Lock()
@@ -124,3 +191,9 @@ func (a *BaseApp[C]) Close(ctx context.Context) error {
 
 	return errs
 }
+
+// CloseLog closes the outputs of the app logger, the entries logged afterwards are written to stderr.
+// It's not a part of Close, since the shutdown is logged after it.
+func (a *BaseApp[C]) CloseLog() error {
+	return a.closeLog()
+}
@@ -0,0 +1,28 @@
+package the
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/heffcodex/the/tcfg"
+)
+
+func TestNewBaseApp_InvalidLogConfig(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("app:\n  name: test\n  key: \"00000000000000000000000000000000\"\nlog:\n  caller: bogus\n"), 0o600))
+
+	v := viper.New()
+	v.SetConfigFile(file)
+
+	assert.NotPanics(t, func() {
+		_, err := NewBaseApp(tcfg.NewLoader[testConfig](v))
+		require.ErrorContains(t, err, "new logger")
+	})
+}
@@ -61,7 +61,20 @@ func (c *Cmd[A, C]) makeRoot(shut *shutter) (*cobra.Command, error) {
 			cancelFn := cmdInject[A, C](cmd, app, shut)
 			timeout := app.C().ShutdownTimeout()
 
-			shut.setup(app.L().Named("cmd"), cancelFn, app.Close, timeout)
+			var (
+				reporter Reporter
+				closeLog func() error
+			)
+
+			if r, ok := any(app).(Reporting); ok {
+				reporter = r.R()
+			}
+
+			if lc, ok := any(app).(LogCloser); ok {
+				closeLog = lc.CloseLog
+			}
+
+			shut.setup(app.L().Named("cmd"), reporter, cancelFn, app.Close, closeLog, timeout)
 			go func() {
 				shut.rootWaitInterrupt()
 				shut.cancel()
@@ -0,0 +1,178 @@
+package the
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/heffcodex/the/tzap"
+)
+
+const (
+	reportFatalFlushTimeout = 5 * time.Second
+)
+
+var (
+	_ Reporter     = (*switchReporter)(nil)
+	_ zapcore.Core = (*reportCore)(nil)
+)
+
+// ReportEvent is an error report built from a log entry.
+type ReportEvent struct {
+	Time    time.Time
+	Level   zapcore.Level
+	Logger  string
+	Message string
+	Error   error  // the `error` field of the entry if any
+	Stack   string // in the zap format: function and `\tfile:line` lines
+	Fields  map[string]any
+}
+
+// RequestID returns the request ID from the fields, see [tzap.WithRequestID].
+func (e *ReportEvent) RequestID() string {
+	id, _ := e.Fields[tzap.KeyRequestID].(string)
+	return id
+}
+
+// Reporter sends error reports somewhere, e.g. [SentryReporter].
+// Report must not block, Flush sends everything reported so far.
+type Reporter interface {
+	Report(ev *ReportEvent)
+	Flush(ctx context.Context) error
+}
+
+// switchReporter forwards reports to the reporter set last, so it can be replaced once loggers are built.
+type switchReporter struct {
+	r atomic.Pointer[Reporter]
+}
+
+func (s *switchReporter) Set(r Reporter) {
+	s.r.Store(&r)
+}
+
+func (s *switchReporter) Current() Reporter {
+	if r := s.r.Load(); r != nil {
+		return *r
+	}
+
+	return nil
+}
+
+func (s *switchReporter) Report(ev *ReportEvent) {
+	if r := s.Current(); r != nil {
+		r.Report(ev)
+	}
+}
+
+func (s *switchReporter) Flush(ctx context.Context) error {
+	if r := s.Current(); r != nil {
+		return r.Flush(ctx)
+	}
+
+	return nil
+}
+
+// reportCore turns the entries at or above the level into [ReportEvent]s, it's meant to be teed with an I/O core.
+// Fatal and panic entries are flushed right away, since the process is about to exit.
+type reportCore struct {
+	zapcore.LevelEnabler
+	reporter Reporter
+	fields   []zapcore.Field
+}
+
+// newReportCore returns the core expanding [tzap.Context] fields, so the trace of the request is reported as well.
+func newReportCore(reporter Reporter, le zapcore.LevelEnabler) zapcore.Core {
+	return tzap.NewTraceCore(&reportCore{LevelEnabler: le, reporter: reporter})
+}
+
+func (c *reportCore) With(fields []zapcore.Field) zapcore.Core {
+	return &reportCore{
+		LevelEnabler: c.LevelEnabler,
+		reporter:     c.reporter,
+		fields:       append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+// Enabled reports nothing until a reporter is set, so the entries aren't encoded for nothing.
+func (c *reportCore) Enabled(lvl zapcore.Level) bool {
+	if s, ok := c.reporter.(*switchReporter); ok && s.Current() == nil {
+		return false
+	}
+
+	return c.LevelEnabler.Enabled(lvl)
+}
+
+func (c *reportCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+func (c *reportCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ev := &ReportEvent{
+		Time:    ent.Time,
+		Level:   ent.Level,
+		Logger:  ent.LoggerName,
+		Message: ent.Message,
+		Stack:   ent.Stack,
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+
+	for _, f := range append(c.fields[:len(c.fields):len(c.fields)], fields...) {
+		if ev.Error == nil {
+			ev.Error, _ = tzap.FieldError(f)
+		}
+
+		if f.Type == zapcore.StringType && ev.Stack == "" && (f.Key == "stack" || f.Key == tzap.KeyStacktrace) {
+			ev.Stack = f.String
+			continue
+		}
+
+		f.AddTo(enc)
+	}
+
+	if ev.Stack == "" {
+		ev.Stack = reportStack()
+	}
+
+	ev.Fields = enc.Fields
+	c.reporter.Report(ev)
+
+	if ent.Level > zapcore.ErrorLevel {
+		ctx, cancel := context.WithTimeout(context.Background(), reportFatalFlushTimeout)
+		defer cancel()
+
+		return c.reporter.Flush(ctx)
+	}
+
+	return nil
+}
+
+func (*reportCore) Sync() error {
+	return nil
+}
+
+// reportStack returns the stack of the caller of the logger, skipping zap and the cores.
+func reportStack() string {
+	stack := zap.StackSkip("", 0).String
+	lines := strings.Split(stack, "\n")
+
+	for i := 0; i+1 < len(lines); i += 2 {
+		fn := lines[i]
+		if !strings.HasPrefix(fn, "go.uber.org/zap") &&
+			!strings.HasPrefix(fn, "github.com/heffcodex/the/tzap.") &&
+			!strings.HasPrefix(fn, "github.com/heffcodex/the.(*reportCore)") &&
+			!strings.HasPrefix(fn, "github.com/heffcodex/the.reportStack") {
+			return strings.Join(lines[i:], "\n")
+		}
+	}
+
+	return stack
+}
@@ -0,0 +1,382 @@
+package the
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/heffcodex/the/tzap"
+)
+
+const (
+	SentryBatchSizeDefault     = 100
+	SentryFlushIntervalDefault = 5 * time.Second
+	SentryTimeoutDefault       = 10 * time.Second
+
+	sentryQueueSizeFactor = 10
+	sentryClient          = "heffcodex-the/1"
+	sentryEventIDLen      = 16
+)
+
+var (
+	ErrInvalidDSN    = errors.New("invalid DSN")
+	ErrReportRefused = errors.New("report refused")
+)
+
+var _ Reporter = (*SentryReporter)(nil)
+
+type SentryConfig struct {
+	DSN           string
+	App           string
+	Env           string
+	Version       string
+	BatchSize     int           // events sent per round, [SentryBatchSizeDefault] if zero; up to 10x more are queued before dropping
+	FlushInterval time.Duration // period of rounds, [SentryFlushIntervalDefault] if zero; also bounds the time of a round
+	Client        *http.Client  // with [SentryTimeoutDefault] if nil
+}
+
+// SentryReporter sends events using the Sentry envelope protocol over HTTP.
+// Events are queued and a single background goroutine sends them in rounds, every FlushInterval or once BatchSize
+// events are queued. An envelope holds one event, so each event is a request of its own and the rate is bounded
+// only by the endpoint latency. A slow or failing endpoint never blocks logging: the events beyond the queue are
+// dropped instead, see [SentryReporter.Dropped]. Close must be called to stop the goroutine.
+type SentryReporter struct {
+	cfg      SentryConfig
+	endpoint string
+	auth     string
+
+	mu      sync.Mutex
+	queue   []*ReportEvent
+	sending sync.Mutex // serializes rounds
+
+	dropped   atomic.Uint64
+	wake      chan struct{}
+	stop      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+func NewSentryReporter(cfg SentryConfig) (*SentryReporter, error) {
+	endpoint, key, err := parseSentryDSN(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = SentryBatchSizeDefault
+	}
+
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = SentryFlushIntervalDefault
+	}
+
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: SentryTimeoutDefault}
+	}
+
+	r := &SentryReporter{
+		cfg:      cfg,
+		endpoint: endpoint,
+		auth:     "Sentry sentry_version=7, sentry_client=" + sentryClient + ", sentry_key=" + key,
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+
+	go r.run()
+
+	return r, nil
+}
+
+// parseSentryDSN returns the envelope endpoint and the public key of `scheme://key@host[/path]/project`.
+func parseSentryDSN(dsn string) (string, string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %w", ErrInvalidDSN, err)
+	}
+
+	path, project, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	if project == "" {
+		path, project = "", path
+	}
+
+	if u.Host == "" || u.User == nil || u.User.Username() == "" || project == "" {
+		return "", "", fmt.Errorf("%w: want scheme://key@host/project", ErrInvalidDSN)
+	}
+
+	if path != "" {
+		path = "/" + path
+	}
+
+	return u.Scheme + "://" + u.Host + path + "/api/" + project + "/envelope/", u.User.Username(), nil
+}
+
+// Report queues the event, it's dropped if the queue is full.
+func (r *SentryReporter) Report(ev *ReportEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.queue) >= r.cfg.BatchSize*sentryQueueSizeFactor {
+		r.dropped.Add(1)
+		return
+	}
+
+	r.queue = append(r.queue, ev)
+
+	if len(r.queue) >= r.cfg.BatchSize {
+		select {
+		case r.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Dropped returns the number of events dropped because of the full queue.
+func (r *SentryReporter) Dropped() uint64 {
+	return r.dropped.Load()
+}
+
+// Flush sends all the queued events.
+func (r *SentryReporter) Flush(ctx context.Context) error {
+	var errs error
+
+	for {
+		sent, err := r.sendBatch(ctx)
+		errs = errors.Join(errs, err)
+
+		if sent == 0 || ctx.Err() != nil {
+			return errs
+		}
+	}
+}
+
+// Close flushes the queue and stops the background goroutine.
+func (r *SentryReporter) Close(ctx context.Context) error {
+	r.closeOnce.Do(func() { close(r.stop) })
+	<-r.stopped
+
+	return r.Flush(ctx)
+}
+
+func (r *SentryReporter) run() {
+	defer close(r.stopped)
+
+	t := time.NewTicker(r.cfg.FlushInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-t.C:
+		case <-r.wake:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), r.cfg.FlushInterval)
+		_, _ = r.sendBatch(ctx) // nowhere to report errors of the reporter
+		cancel()
+	}
+}
+
+func (r *SentryReporter) sendBatch(ctx context.Context) (int, error) {
+	r.sending.Lock()
+	defer r.sending.Unlock()
+
+	r.mu.Lock()
+	n := min(len(r.queue), r.cfg.BatchSize)
+	batch := slices.Clone(r.queue[:n])
+	r.queue = slices.Delete(r.queue, 0, n)
+	r.mu.Unlock()
+
+	var errs error
+
+	for _, ev := range batch {
+		errs = errors.Join(errs, r.send(ctx, ev))
+	}
+
+	return n, errs
+}
+
+// send posts an envelope with a single event item, the protocol allows no more.
+func (r *SentryReporter) send(ctx context.Context, ev *ReportEvent) error {
+	id := newSentryEventID()
+
+	payload, err := json.Marshal(r.event(id, ev))
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	var body bytes.Buffer
+
+	_ = json.NewEncoder(&body).Encode(map[string]any{"event_id": id, "sent_at": time.Now().UTC().Format(time.RFC3339Nano)})
+	_ = json.NewEncoder(&body).Encode(map[string]any{"type": "event", "length": len(payload), "content_type": "application/json"})
+	body.Write(payload)
+	body.WriteByte('\n')
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, &body)
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", r.auth)
+
+	resp, err := r.cfg.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send event: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%w: %s", ErrReportRefused, resp.Status)
+	}
+
+	return nil
+}
+
+//nolint:tagliatelle // Sentry protocol
+type sentryEvent struct {
+	EventID     string                    `json:"event_id"`
+	Timestamp   string                    `json:"timestamp"`
+	Platform    string                    `json:"platform"`
+	Level       string                    `json:"level"`
+	Logger      string                    `json:"logger,omitempty"`
+	ServerName  string                    `json:"server_name,omitempty"`
+	Release     string                    `json:"release,omitempty"`
+	Environment string                    `json:"environment,omitempty"`
+	Message     *sentryMessage            `json:"logentry,omitempty"`
+	Exception   *sentryException          `json:"exception,omitempty"`
+	Tags        map[string]string         `json:"tags,omitempty"`
+	Extra       map[string]any            `json:"extra,omitempty"`
+	Contexts    map[string]map[string]any `json:"contexts,omitempty"`
+}
+
+type sentryMessage struct {
+	Formatted string `json:"formatted"`
+}
+
+type sentryException struct {
+	Values []sentryExc `json:"values"`
+}
+
+type sentryExc struct {
+	Type       string            `json:"type"`
+	Value      string            `json:"value"`
+	Stacktrace *sentryStacktrace `json:"stacktrace,omitempty"`
+}
+
+type sentryStacktrace struct {
+	Frames []sentryFrame `json:"frames"`
+}
+
+//nolint:tagliatelle // Sentry protocol
+type sentryFrame struct {
+	Function string `json:"function"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+}
+
+func (r *SentryReporter) event(id string, ev *ReportEvent) *sentryEvent {
+	e := &sentryEvent{
+		EventID:     id,
+		Timestamp:   ev.Time.UTC().Format(time.RFC3339Nano),
+		Platform:    "go",
+		Level:       sentryLevel(ev.Level),
+		Logger:      ev.Logger,
+		ServerName:  r.cfg.App,
+		Release:     r.cfg.Version,
+		Environment: r.cfg.Env,
+		Message:     &sentryMessage{Formatted: ev.Message},
+		Tags:        map[string]string{"app": r.cfg.App},
+		Extra:       ev.Fields,
+	}
+
+	if id := ev.RequestID(); id != "" {
+		e.Tags[tzap.KeyRequestID] = id
+	}
+
+	if traceID, ok := ev.Fields[tzap.KeyTraceID].(string); ok {
+		e.Contexts = map[string]map[string]any{"trace": {"trace_id": traceID, "span_id": ev.Fields[tzap.KeySpanID]}}
+	}
+
+	exc := sentryExc{Type: "log", Value: ev.Message}
+
+	if ev.Error != nil {
+		exc.Type = reflect.TypeOf(ev.Error).String()
+		exc.Value = ev.Error.Error()
+	}
+
+	if frames := sentryFrames(ev.Stack); len(frames) > 0 {
+		exc.Stacktrace = &sentryStacktrace{Frames: frames}
+	}
+
+	if ev.Error != nil || exc.Stacktrace != nil {
+		e.Exception = &sentryException{Values: []sentryExc{exc}}
+	}
+
+	return e
+}
+
+func sentryLevel(lvl zapcore.Level) string {
+	switch {
+	case lvl < zapcore.InfoLevel:
+		return "debug"
+	case lvl < zapcore.WarnLevel:
+		return "info"
+	case lvl < zapcore.ErrorLevel:
+		return "warning"
+	case lvl < zapcore.DPanicLevel:
+		return "error"
+	default:
+		return "fatal"
+	}
+}
+
+// sentryFrames parses a zap stack, Sentry wants the frames from the outermost one.
+func sentryFrames(stack string) []sentryFrame {
+	lines := strings.Split(strings.TrimSpace(stack), "\n")
+	frames := make([]sentryFrame, 0, len(lines)/2)
+
+	for i := 0; i+1 < len(lines); i += 2 {
+		file := strings.TrimSpace(lines[i+1])
+
+		var lineno int
+
+		if idx := strings.LastIndexByte(file, ':'); idx >= 0 {
+			lineno, _ = strconv.Atoi(file[idx+1:])
+			file = file[:idx]
+		}
+
+		frames = append(frames, sentryFrame{Function: lines[i], AbsPath: file, Lineno: lineno})
+	}
+
+	slices.Reverse(frames)
+
+	return frames
+}
+
+func newSentryEventID() string {
+	b := make([]byte, sentryEventIDLen)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,158 @@
+package the
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/heffcodex/the/tzap"
+)
+
+func TestParseSentryDSN(t *testing.T) {
+	t.Parallel()
+
+	endpoint, key, err := parseSentryDSN("https://abc@sentry.example.com/prefix/42")
+	require.NoError(t, err)
+	assert.Equal(t, "https://sentry.example.com/prefix/api/42/envelope/", endpoint)
+	assert.Equal(t, "abc", key)
+
+	_, _, err = parseSentryDSN("https://sentry.example.com/42")
+	require.ErrorIs(t, err, ErrInvalidDSN)
+}
+
+func TestSentryReporter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu     sync.Mutex
+		events []map[string]any
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/42/envelope/", r.URL.Path)
+		assert.Contains(t, r.Header.Get("X-Sentry-Auth"), "sentry_key=key")
+
+		sc := bufio.NewScanner(r.Body)
+		sc.Buffer(nil, 1<<20)
+
+		var lines []string
+		for sc.Scan() {
+			lines = append(lines, sc.Text())
+		}
+
+		if !assert.Len(t, lines, 3) {
+			return
+		}
+
+		var ev map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(lines[2]), &ev))
+
+		mu.Lock()
+		events = append(events, ev)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	reporter, err := NewSentryReporter(SentryConfig{
+		DSN:           strings.Replace(srv.URL, "://", "://key@", 1) + "/42",
+		App:           "test",
+		Env:           "prod",
+		Version:       "v1.2.3",
+		FlushInterval: time.Hour,
+	})
+	require.NoError(t, err)
+
+	log := zap.New(newReportCore(reporter, zapcore.ErrorLevel)).Named("db")
+	ctx := tzap.WithRequestID(t.Context(), "req-1")
+
+	log.Warn("not reported")
+	log.With(zap.String(tzap.KeyRequestID, tzap.RequestID(ctx))).Error("ping failed", tzap.Err(errors.New("boom")))
+
+	require.NoError(t, reporter.Close(t.Context()))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Len(t, events, 1)
+
+	ev := events[0]
+	assert.Equal(t, "error", ev["level"])
+	assert.Equal(t, "db", ev["logger"])
+	assert.Equal(t, "prod", ev["environment"])
+	assert.Equal(t, "v1.2.3", ev["release"])
+	assert.Equal(t, map[string]any{"app": "test", tzap.KeyRequestID: "req-1"}, ev["tags"])
+	assert.Equal(t, map[string]any{"formatted": "ping failed"}, ev["logentry"])
+
+	exc := ev["exception"].(map[string]any)["values"].([]any)[0].(map[string]any) //nolint:errcheck // test
+	assert.Equal(t, "boom", exc["value"])
+
+	frames := exc["stacktrace"].(map[string]any)["frames"].([]any) //nolint:errcheck // test
+	require.NotEmpty(t, frames)
+	assert.Contains(t, frames[len(frames)-1].(map[string]any)["function"], "TestSentryReporter") //nolint:errcheck // test
+}
+
+func TestSentryReporter_SlowEndpoint(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	reporter, err := NewSentryReporter(SentryConfig{
+		DSN:           strings.Replace(srv.URL, "://", "://key@", 1) + "/42",
+		BatchSize:     1,
+		FlushInterval: time.Hour,
+	})
+	require.NoError(t, err)
+
+	log := zap.New(newReportCore(reporter, zapcore.ErrorLevel))
+	start := time.Now()
+
+	for range 100 {
+		log.Error("failed")
+	}
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Positive(t, reporter.Dropped())
+
+	close(release)
+	require.ErrorIs(t, reporter.Close(t.Context()), ErrReportRefused)
+}
+
+type nopReporter struct{}
+
+func (nopReporter) Report(*ReportEvent) {
+}
+
+func (nopReporter) Flush(context.Context) error {
+	return nil
+}
+
+func TestReportCore_NoReporter(t *testing.T) {
+	t.Parallel()
+
+	reporter := new(switchReporter)
+	core := newReportCore(reporter, zapcore.ErrorLevel)
+	ent := zapcore.Entry{Level: zapcore.ErrorLevel}
+
+	assert.Nil(t, core.Check(ent, nil))
+
+	reporter.Set(nopReporter{})
+	assert.NotNil(t, core.Check(ent, nil))
+}
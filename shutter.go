@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+
+	"github.com/heffcodex/the/tzap"
 )
 
 type shutter struct {
@@ -21,8 +23,10 @@ type shutter struct {
 	// set by setup
 	wasSetup   atomic.Bool
 	log        *zap.Logger
+	reporter   Reporter
 	cancelFn   context.CancelFunc
 	onShutdown CloseFunc
+	closeLog   func() error
 	timeout    time.Duration
 }
 
@@ -38,14 +42,19 @@ func newShutter(signals []os.Signal) *shutter {
 	}
 }
 
-func (s *shutter) setup(log *zap.Logger, cancelFn context.CancelFunc, onShutdown CloseFunc, timeout time.Duration) *shutter {
+func (s *shutter) setup(
+	log *zap.Logger, reporter Reporter, cancelFn context.CancelFunc, onShutdown CloseFunc, closeLog func() error,
+	timeout time.Duration,
+) *shutter {
 	if !s.wasSetup.CompareAndSwap(false, true) {
 		panic("shutter setup called twice")
 	}
 
 	s.log = log
+	s.reporter = reporter
 	s.cancelFn = cancelFn
 	s.onShutdown = onShutdown
+	s.closeLog = closeLog
 	s.timeout = timeout
 
 	return s
@@ -93,7 +102,9 @@ func (s *shutter) down() {
 	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
 	defer func() {
 		cancel()
+		s.flushReports()
 		_ = s.log.Sync() //nolint:wsl // it's ok
+		s.closeOutputs()
 	}()
 
 	onShutdownErr := make(chan error)
@@ -117,7 +128,32 @@ func (s *shutter) down() {
 	if err == nil {
 		s.log.Info("shutdown complete")
 	} else {
-		s.log.Error("shutdown error", zap.Error(err))
+		s.log.Error("shutdown error", tzap.Err(err))
+	}
+}
+
+// flushReports sends the reports left, including the one of a failed shutdown.
+func (s *shutter) flushReports() {
+	if s.reporter == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	if err := s.reporter.Flush(ctx); err != nil {
+		s.log.Warn("flush reports", zap.Error(err))
+	}
+}
+
+// closeOutputs closes the log outputs after the last entry of the shutdown, its error goes to stderr then.
+func (s *shutter) closeOutputs() {
+	if s.closeLog == nil {
+		return
+	}
+
+	if err := s.closeLog(); err != nil {
+		s.log.Error("close log", tzap.Err(err))
 	}
 }
 
@@ -0,0 +1,40 @@
+package the
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestShutter_CloseLog(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	var logged []string
+
+	closeLog := func() error {
+		for _, e := range logs.AllUntimed() {
+			logged = append(logged, e.Message)
+		}
+
+		return nil
+	}
+
+	onShutdown := func(context.Context) error {
+		zap.New(core).Info("closing")
+		return nil
+	}
+
+	s := newShutter([]os.Signal{os.Interrupt})
+	s.setup(zap.New(core), nil, func() {}, onShutdown, closeLog, time.Second)
+	s.down()
+
+	assert.Equal(t, []string{"shutdown start", "closing", "shutdown complete"}, logged)
+}
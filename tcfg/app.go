@@ -1,9 +1,18 @@
 package tcfg
 
+import "time"
+
 type App struct {
 	Name            string `mapstructure:"name" json:"name" yaml:"name"`
 	Key             Key    `mapstructure:"key" json:"key" yaml:"key"`
 	Env             Env    `mapstructure:"env" json:"env" yaml:"env"`
+	Version         string `mapstructure:"version" json:"version" yaml:"version"`
 	LogLevel        string `mapstructure:"logLevel" json:"logLevel" yaml:"logLevel"`
-	ShutdownTimeout int    `mapstructure:"shutdownTimeout" json:"shutdownTimeout" yaml:"shutdownTimeout"`
+	ShutdownTimeout int    `mapstructure:"shutdownTimeout" json:"shutdownTimeout" yaml:"shutdownTimeout"` // seconds
+}
+
+func (a *App) Defaults() {
+	a.Env = AppEnvDefault
+	a.LogLevel = AppLogLevelDefault.String()
+	a.ShutdownTimeout = int(AppShutdownTimeoutDefault / time.Second)
 }
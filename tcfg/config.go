@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime/debug"
 	"time"
 
 	"github.com/spf13/viper"
@@ -14,23 +15,32 @@ type Config interface {
 	AppName() string
 	AppKey() Key
 	AppEnv() Env
+	AppVersion() string
 	LogLevel() string
+	LogConfig() Log
+	ReportConfig() Report
 	ShutdownTimeout() time.Duration
 
 	BeforeRead(v *viper.Viper) error
 	AfterRead(v *viper.Viper) error
 }
 
+// Defaults of [BaseConfig], applied by [SetDefaults] through the Defaults methods of its parts.
+// The getters fall back to them as well, for the configs built without a [Loader] and the invalid values.
 const (
 	AppEnvDefault             = EnvDev
 	AppLogLevelDefault        = zap.InfoLevel
+	AppLogOutputDefault       = LogOutputStderr
+	AppReportLevelDefault     = zap.ErrorLevel
 	AppShutdownTimeoutDefault = 15 * time.Second
 )
 
 var _ Config = (*BaseConfig)(nil)
 
 type BaseConfig struct {
-	App App `mapstructure:"app" json:"app" yaml:"app"`
+	App    App    `mapstructure:"app" json:"app" yaml:"app"`
+	Log    Log    `mapstructure:"log" json:"log" yaml:"log"`
+	Report Report `mapstructure:"report" json:"report" yaml:"report"`
 }
 
 func (c BaseConfig) AppName() string {
@@ -53,6 +63,19 @@ func (c BaseConfig) AppEnv() Env {
 	return c.App.Env
 }
 
+// AppVersion falls back to the main module version of the binary, empty for development builds.
+func (c BaseConfig) AppVersion() string {
+	if c.App.Version != "" {
+		return c.App.Version
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+
+	return ""
+}
+
 func (c BaseConfig) LogLevel() string {
 	if c.App.LogLevel == "" {
 		return AppLogLevelDefault.String()
@@ -61,6 +84,34 @@ func (c BaseConfig) LogLevel() string {
 	return c.App.LogLevel
 }
 
+func (c BaseConfig) LogConfig() Log {
+	l := c.Log
+
+	if l.Format == "" {
+		if c.AppEnv() == EnvDev {
+			l.Format = LogFormatPretty
+		} else {
+			l.Format = LogFormatJSON
+		}
+	}
+
+	if len(l.Output) == 0 {
+		l.Output = []string{AppLogOutputDefault}
+	}
+
+	return l
+}
+
+func (c BaseConfig) ReportConfig() Report {
+	r := c.Report
+
+	if r.Level == "" {
+		r.Level = AppReportLevelDefault.String()
+	}
+
+	return r
+}
+
 func (c BaseConfig) ShutdownTimeout() time.Duration {
 	if c.App.ShutdownTimeout < 1 {
 		return AppShutdownTimeoutDefault
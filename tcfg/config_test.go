@@ -40,6 +40,18 @@ func TestBaseConfig_LogLevel(t *testing.T) {
 	assert.Equal(t, "foo", BaseConfig{App: App{LogLevel: "foo"}}.LogLevel())
 }
 
+func TestBaseConfig_LogConfig(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, Log{Format: LogFormatPretty, Output: []string{"stderr"}}, BaseConfig{}.LogConfig())
+	assert.Equal(t, Log{Format: LogFormatJSON, Output: []string{"stderr"}}, BaseConfig{App: App{Env: EnvProd}}.LogConfig())
+	assert.Equal(
+		t,
+		Log{Format: LogFormatLogfmt, Output: []string{"stdout", "app.log"}},
+		BaseConfig{Log: Log{Format: LogFormatLogfmt, Output: []string{"stdout", "app.log"}}}.LogConfig(),
+	)
+}
+
 func TestBaseConfig_ShutdownTimeout(t *testing.T) {
 	t.Parallel()
 
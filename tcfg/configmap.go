@@ -0,0 +1,241 @@
+package tcfg
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	ConfigMapDataLink            = "..data"
+	ConfigMapPollIntervalDefault = 10 * time.Second
+)
+
+var (
+	configMapFragmentExts = []string{"yaml", "yml", "json", "toml"}
+)
+
+// ReadConfigMapDir replaces the config of viper with the key tree of the directory, like a mounted Kubernetes ConfigMap.
+//
+// Files with a config extension (yaml, yml, json, toml) are fragments merged in the lexical order of their names,
+// other files hold the value of the key named after them, with the trailing newline trimmed. The key of a file
+// nested in subdirectories is prefixed with their names, and so are the keys of a fragment there. Hidden entries are skipped.
+//
+// If the directory has the [ConfigMapDataLink] symlink, the files are read from its target, so the set is consistent
+// even if Kubernetes swaps it meanwhile.
+func ReadConfigMapDir(v *viper.Viper, dir string) error {
+	root, err := resolveConfigMapDir(dir)
+	if err != nil {
+		return err
+	}
+
+	tree := viper.New()
+
+	if err := readConfigMapTree(tree, root, ""); err != nil {
+		return err
+	}
+
+	if err := resetConfig(v); err != nil {
+		return fmt.Errorf("reset: %w", err)
+	}
+
+	return v.MergeConfigMap(tree.AllSettings())
+}
+
+// resetConfig empties the config layer of viper by reading an empty document in its own config type,
+// so the config files read by it later are decoded as before. A viper without a usable type is set to yaml.
+func resetConfig(v *viper.Viper) error {
+	for _, empty := range []string{"", "{}"} { // json has no empty document
+		if err := v.ReadConfig(strings.NewReader(empty)); err == nil {
+			return nil
+		}
+	}
+
+	v.SetConfigType("yaml")
+
+	return v.ReadConfig(strings.NewReader(""))
+}
+
+func resolveConfigMapDir(dir string) (string, error) {
+	root, err := filepath.EvalSymlinks(filepath.Join(dir, ConfigMapDataLink))
+	if err == nil {
+		return root, nil
+	}
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return dir, nil
+	}
+
+	return "", fmt.Errorf("resolve %s: %w", ConfigMapDataLink, err)
+}
+
+func readConfigMapTree(v *viper.Viper, dir, prefix string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("read dir: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+
+		info, err := os.Stat(path) // follows the symlinks of the plain directories
+		if err != nil {
+			return fmt.Errorf("stat %s: %w", name, err)
+		}
+
+		if info.IsDir() {
+			if err := readConfigMapTree(v, path, joinKey(prefix, name)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := readConfigMapFile(v, path, prefix); err != nil {
+			return fmt.Errorf("read %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+func readConfigMapFile(v *viper.Viper, path, prefix string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	name := filepath.Base(path)
+	ext := strings.TrimPrefix(filepath.Ext(name), ".")
+
+	if !slices.Contains(configMapFragmentExts, ext) {
+		return v.MergeConfigMap(nestSettings(joinKey(prefix, name), strings.TrimRight(string(b), "\r\n")))
+	}
+
+	fragment := viper.New()
+	fragment.SetConfigType(ext)
+
+	if err := fragment.ReadConfig(bytes.NewReader(b)); err != nil {
+		return err
+	}
+
+	if prefix == "" {
+		return v.MergeConfigMap(fragment.AllSettings())
+	}
+
+	return v.MergeConfigMap(nestSettings(prefix, fragment.AllSettings()))
+}
+
+// nestSettings returns the settings map with the value at the dot-separated key.
+func nestSettings(key string, value any) map[string]any {
+	parts := strings.Split(key, ".")
+
+	for i := len(parts) - 1; i > 0; i-- {
+		value = map[string]any{parts[i]: value}
+	}
+
+	return map[string]any{parts[0]: value}
+}
+
+// ConfigMapVersion identifies the content of the directory: the target of [ConfigMapDataLink] if any,
+// a digest of the names, sizes and modification times of the files otherwise.
+func ConfigMapVersion(dir string) (string, error) {
+	if target, err := os.Readlink(filepath.Join(dir, ConfigMapDataLink)); err == nil {
+		return target, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("read %s: %w", ConfigMapDataLink, err)
+	}
+
+	h := sha256.New()
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+
+		_, _ = h.Write([]byte(path + "\x00" + strconv.FormatInt(info.Size(), 10) + "\x00" + info.ModTime().String() + "\x00"))
+
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("walk: %w", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// WatchConfigMapDir polls [ConfigMapVersion] of the directory every interval ([ConfigMapPollIntervalDefault] if not positive)
+// and calls fn once it changes, until the context is done. If fn fails, the version isn't taken as seen
+// and fn is called again on the next poll, so a reload failed on a half-written directory is retried.
+//
+// A swap of [ConfigMapDataLink] is reported right away, since Kubernetes makes it once all the files are written.
+// For the other directories the change is reported once the version stays the same for an interval.
+func WatchConfigMapDir(ctx context.Context, dir string, interval time.Duration, fn func() error) error {
+	if interval <= 0 {
+		interval = ConfigMapPollIntervalDefault
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	return watchConfigMapDir(ctx, dir, t.C, fn)
+}
+
+// watchConfigMapDir polls the directory on every tick, see [WatchConfigMapDir].
+func watchConfigMapDir(ctx context.Context, dir string, ticks <-chan time.Time, fn func() error) error {
+	current, err := ConfigMapVersion(dir)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Lstat(filepath.Join(dir, ConfigMapDataLink))
+	hasDataLink := err == nil
+	pending := current
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticks:
+		}
+
+		version, err := ConfigMapVersion(dir)
+		if err != nil || version == current {
+			pending = current
+			continue
+		}
+
+		if !hasDataLink && version != pending {
+			pending = version
+			continue
+		}
+
+		pending = version
+
+		if err := fn(); err == nil {
+			current = version
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package tcfg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigMap mimics the Kubernetes atomic writer: files go to a new timestamped directory,
+// then the ..data symlink is swapped to it.
+func writeConfigMap(t *testing.T, dir, version string, files map[string]string) {
+	t.Helper()
+
+	data := filepath.Join(dir, version)
+
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(data, name)), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(data, name), []byte(content), 0o600))
+
+		top := strings.Split(name, "/")[0]
+		if _, err := os.Lstat(filepath.Join(dir, top)); os.IsNotExist(err) {
+			require.NoError(t, os.Symlink(filepath.Join(ConfigMapDataLink, top), filepath.Join(dir, top)))
+		}
+	}
+
+	tmp := filepath.Join(dir, "..data_tmp")
+	require.NoError(t, os.Symlink(version, tmp))
+	require.NoError(t, os.Rename(tmp, filepath.Join(dir, ConfigMapDataLink)))
+}
+
+// tick sends a poll tick, which the watcher takes once done with the previous one.
+func tick(t *testing.T, ticks chan<- time.Time) {
+	t.Helper()
+
+	select {
+	case ticks <- time.Now():
+	case <-time.After(time.Second):
+		require.Fail(t, "not polled")
+	}
+}
+
+func TestReadConfigMapDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeConfigMap(t, dir, "..v1", map[string]string{
+		"10-app.yaml":       "app:\n  name: first\n  env: test\nlog:\n  format: json\n",
+		"20-app.yaml":       "app:\n  name: second\n",
+		"app.key":           "0123456789abcdef0123456789abcdef\n",
+		"report/dsn":        "https://key@sentry.example.com/1",
+		"report/extra.json": `{"level": "warn"}`,
+	})
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(strings.NewReader("unrelated: true")))
+
+	require.NoError(t, ReadConfigMapDir(v, dir))
+	assert.Equal(t, "second", v.GetString("app.name"))
+	assert.Equal(t, "test", v.GetString("app.env"))
+	assert.Equal(t, "json", v.GetString("log.format"))
+	assert.Equal(t, "0123456789abcdef0123456789abcdef", v.GetString("app.key"))
+	assert.Equal(t, "https://key@sentry.example.com/1", v.GetString("report.dsn"))
+	assert.Equal(t, "warn", v.GetString("report.level"))
+	assert.False(t, v.IsSet("unrelated"))
+}
+
+func TestReadConfigMapDir_ConfigType(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeConfigMap(t, dir, "..v1", map[string]string{"app.name": "dir"})
+
+	for name, content := range map[string]string{
+		"config.toml": "[app]\nname = \"file\"\n",
+		"config.json": `{"app": {"name": "file"}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			file := filepath.Join(t.TempDir(), name)
+			require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+
+			v := viper.New()
+			v.SetConfigFile(file)
+
+			require.NoError(t, ReadConfigMapDir(v, dir))
+			assert.Equal(t, "dir", v.GetString("app.name"))
+
+			require.NoError(t, v.ReadInConfig())
+			assert.Equal(t, "file", v.GetString("app.name"))
+		})
+	}
+}
+
+func TestLoader_Watch(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeConfigMap(t, dir, "..v1", map[string]string{
+		"app.name": "v1",
+		"app.key":  "0123456789abcdef0123456789abcdef",
+	})
+
+	_, err := NewLoader[BaseConfig](viper.New(), ConfigMapDir(dir), Layered()).Get()
+	require.ErrorIs(t, err, ErrIncompatibleOptions)
+
+	l := NewLoader[BaseConfig](viper.New(), ConfigMapDir(dir))
+
+	c, err := l.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "v1", c.App.Name)
+
+	v1, err := ConfigMapVersion(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "..v1", v1)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	ticks := make(chan time.Time)
+	reloaded := make(chan BaseConfig, 1)
+
+	go func() {
+		assert.NoError(t, watchConfigMapDir(ctx, dir, ticks, l.reloader(func(c BaseConfig, err error) {
+			assert.NoError(t, err)
+			reloaded <- c
+		})))
+	}()
+
+	tick(t, ticks) // v1 is seen by now
+	writeConfigMap(t, dir, "..v2", map[string]string{
+		"app.name": "v2",
+		"app.key":  "0123456789abcdef0123456789abcdef",
+	})
+	tick(t, ticks)
+
+	select {
+	case c := <-reloaded:
+		assert.Equal(t, "v2", c.App.Name)
+	case <-time.After(time.Second):
+		require.Fail(t, "not reloaded")
+	}
+
+	c, err = l.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "v2", c.App.Name)
+
+	require.ErrorIs(t, NewLoader[BaseConfig](viper.New()).Watch(ctx, 0, nil), ErrNotWatchable)
+}
+
+func TestWatchConfigMapDir_Retry(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeConfigMap(t, dir, "..v1", map[string]string{"app.name": "v1"})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	ticks := make(chan time.Time)
+	calls := make(chan int, 10)
+	done := make(chan error, 1)
+
+	go func() {
+		n := 0
+
+		done <- watchConfigMapDir(ctx, dir, ticks, func() error {
+			n++
+			calls <- n
+
+			if n == 1 {
+				return assert.AnError
+			}
+
+			return nil
+		})
+	}()
+
+	tick(t, ticks) // v1 is seen by now
+	writeConfigMap(t, dir, "..v2", map[string]string{"app.name": "v2"})
+
+	for range 4 { // fails, retries, then sees v2 twice, the last tick waiting for the poll before it
+		tick(t, ticks)
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+	close(calls)
+
+	var got []int
+	for n := range calls {
+		got = append(got, n)
+	}
+
+	assert.Equal(t, []int{1, 2}, got)
+}
@@ -0,0 +1,207 @@
+package tcfg
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultTag = "default"
+)
+
+var (
+	ErrInvalidDefault = errors.New("invalid default")
+
+	durationType = reflect.TypeFor[time.Duration]()
+)
+
+// Defaulter is implemented by config structs, nested ones included, to set defaults that can't be expressed
+// with `default` tags. Defaults is called on a struct with the tag defaults already applied.
+type Defaulter interface {
+	Defaults()
+}
+
+// SetDefaults registers the defaults of the config type in viper, so they are visible in the read config
+// and overridden by files and env the same way as any other value.
+//
+// A default comes from the `default` tag of a field, parsed according to the field type
+// (durations as `15s`, lists as comma-separated values), or from [Defaulter] of the struct holding it.
+func SetDefaults(v *viper.Viper, config any) error {
+	t := reflect.TypeOf(config)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	rv := reflect.New(t).Elem()
+
+	if err := applyDefaults(rv, ""); err != nil {
+		return err
+	}
+
+	walkLeaves(rv, "", func(key string, fv reflect.Value) {
+		if !fv.IsZero() {
+			v.SetDefault(key, fv.Interface())
+		}
+	})
+
+	return nil
+}
+
+func applyDefaults(v reflect.Value, path string) error {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key, ok := fieldKey(field, path)
+		if !ok {
+			continue
+		}
+
+		fv := v.Field(i)
+
+		if tag, ok := field.Tag.Lookup(defaultTag); ok {
+			if err := setDefault(fv, tag); err != nil {
+				return fmt.Errorf("%w: %s: %w", ErrInvalidDefault, key, err)
+			}
+		}
+
+		if fv.Kind() == reflect.Struct {
+			if err := applyDefaults(fv, key); err != nil {
+				return err
+			}
+		}
+	}
+
+	if d, ok := v.Addr().Interface().(Defaulter); ok {
+		d.Defaults()
+	}
+
+	return nil
+}
+
+func setDefault(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetFloat(n)
+	case reflect.Slice:
+		parts := strings.Split(s, ",")
+		slice := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+
+		for i, part := range parts {
+			if err := setDefault(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+
+		v.Set(slice)
+	default:
+		return fmt.Errorf("%w: %s", errors.ErrUnsupported, v.Type())
+	}
+
+	return nil
+}
+
+// walkLeaves calls fn for every non-struct field and every struct without exported fields (like time.Time).
+func walkLeaves(v reflect.Value, path string, fn func(key string, v reflect.Value)) {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key, ok := fieldKey(field, path)
+		if !ok {
+			continue
+		}
+
+		fv := v.Field(i)
+
+		if fv.Kind() == reflect.Struct && hasExportedFields(fv.Type()) {
+			walkLeaves(fv, key, fn)
+		} else {
+			fn(key, fv)
+		}
+	}
+}
+
+// fieldKey returns the viper key of the field, squashed fields share the one of the parent.
+func fieldKey(field reflect.StructField, path string) (string, bool) {
+	name, opts, _ := strings.Cut(field.Tag.Get(mapstructureTag), ",")
+
+	switch {
+	case name == "-":
+		return "", false
+	case slices.Contains(strings.Split(opts, ","), "squash"):
+		return path, true
+	case name == "":
+		name = field.Name
+	}
+
+	return joinKey(path, name), true
+}
+
+func hasExportedFields(t reflect.Type) bool {
+	for i := range t.NumField() {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+
+	return false
+}
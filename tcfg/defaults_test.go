@@ -0,0 +1,60 @@
+package tcfg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDefaultsDB struct {
+	DSN     string        `mapstructure:"dsn"`
+	Timeout time.Duration `mapstructure:"timeout" default:"5s"`
+	Hosts   []string      `mapstructure:"hosts" default:"a, b"`
+}
+
+func (db *testDefaultsDB) Defaults() {
+	db.DSN = "postgres://" + db.Hosts[0]
+}
+
+type testDefaultsConfig struct {
+	BaseConfig `mapstructure:",squash"` //nolint:tagliatelle // test
+
+	DB      testDefaultsDB `mapstructure:"db"`
+	Retries uint8          `mapstructure:"retries" default:"3"`
+	Debug   bool           `mapstructure:"debug" default:"true"`
+}
+
+func TestSetDefaults(t *testing.T) {
+	t.Parallel()
+
+	v := viper.New()
+	require.NoError(t, SetDefaults(v, &testDefaultsConfig{}))
+
+	v.Set("retries", 5)
+
+	var c testDefaultsConfig
+	require.NoError(t, v.Unmarshal(&c))
+
+	assert.Equal(t, testDefaultsDB{DSN: "postgres://a", Timeout: 5 * time.Second, Hosts: []string{"a", "b"}}, c.DB)
+	assert.Equal(t, uint8(5), c.Retries)
+	assert.True(t, c.Debug)
+
+	assert.Equal(t, AppEnvDefault, c.App.Env)
+	assert.Equal(t, AppLogLevelDefault.String(), c.App.LogLevel)
+	assert.Equal(t, AppShutdownTimeoutDefault, c.ShutdownTimeout())
+	assert.Equal(t, []string{AppLogOutputDefault}, c.Log.Output)
+	assert.Equal(t, AppReportLevelDefault.String(), c.Report.Level)
+}
+
+func TestSetDefaults_Invalid(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Port int `mapstructure:"port" default:"http"`
+	}
+
+	require.ErrorIs(t, SetDefaults(viper.New(), config{}), ErrInvalidDefault)
+}
@@ -0,0 +1,34 @@
+package tcfg
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/spf13/viper"
+)
+
+// BindEnv binds every key of the config type to its environment variable, named with the prefix
+// and the key replacer of viper. Unlike [viper.Viper.AutomaticEnv] alone, it makes the keys absent from
+// the config file visible to Unmarshal.
+func BindEnv(v *viper.Viper, config any) error {
+	t := reflect.TypeOf(config)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var err error
+
+	walkLeaves(reflect.New(t).Elem(), "", func(key string, _ reflect.Value) {
+		if err == nil {
+			if bindErr := v.BindEnv(key); bindErr != nil {
+				err = fmt.Errorf("bind %s: %w", key, bindErr)
+			}
+		}
+	})
+
+	return err
+}
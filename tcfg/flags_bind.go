@@ -0,0 +1,54 @@
+package tcfg
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// BindFlags binds the flags named after the keys of the config type, e.g. `--log.format`, case-insensitively.
+// Keys under a map field, like `--log.fields.region`, are bound as well. The other flags are left to the command,
+// so they neither reach the config nor trip [Strict].
+func BindFlags(v *viper.Viper, flags *pflag.FlagSet, config any) error {
+	t := reflect.TypeOf(config)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var keys []string
+
+	walkLeaves(reflect.New(t).Elem(), "", func(key string, _ reflect.Value) {
+		keys = append(keys, strings.ToLower(key))
+	})
+
+	var err error
+
+	flags.VisitAll(func(f *pflag.Flag) {
+		if err != nil || !isConfigKey(keys, strings.ToLower(f.Name)) {
+			return
+		}
+
+		if bindErr := v.BindPFlag(f.Name, f); bindErr != nil {
+			err = fmt.Errorf("bind %s: %w", f.Name, bindErr)
+		}
+	})
+
+	return err
+}
+
+func isConfigKey(keys []string, name string) bool {
+	for _, key := range keys {
+		if name == key || strings.HasPrefix(name, key+".") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,11 +1,19 @@
 package tcfg
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
 )
 
@@ -14,6 +22,14 @@ const (
 	envSuffixFile = "_FILE"
 	envSuffixPath = "_PATH"
 	envSuffixType = "_TYPE"
+
+	layerEnvKey = "app.env"
+)
+
+var (
+	ErrUnknownKeys         = errors.New("unknown keys")
+	ErrNotWatchable        = errors.New("not watchable")
+	ErrIncompatibleOptions = errors.New("incompatible options")
 )
 
 type Loader[C Config] struct {
@@ -21,13 +37,20 @@ type Loader[C Config] struct {
 	loaded bool
 	mutex  sync.RWMutex
 	viper  *viper.Viper
+	opts   loaderOptions
 }
 
-func NewLoader[C Config](v *viper.Viper) *Loader[C] {
-	return &Loader[C]{viper: v}
+func NewLoader[C Config](v *viper.Viper, opts ...LoaderOption) *Loader[C] {
+	l := &Loader[C]{viper: v}
+
+	for _, opt := range opts {
+		opt(&l.opts)
+	}
+
+	return l
 }
 
-func NewDefaultLoader[C Config]() *Loader[C] {
+func NewDefaultLoader[C Config](opts ...LoaderOption) *Loader[C] {
 	v := viper.New()
 
 	if configFile, ok := os.LookupEnv(envPrefix + envSuffixFile); ok { //nolint:nestif // ok
@@ -52,7 +75,7 @@ func NewDefaultLoader[C Config]() *Loader[C] {
 	v.SetEnvPrefix(envPrefix)
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
-	return NewLoader[C](v)
+	return NewLoader[C](v, opts...)
 }
 
 func (l *Loader[C]) Must() C {
@@ -87,6 +110,38 @@ func (l *Loader[C]) Get() (C, error) {
 	return l.config, nil
 }
 
+// Reload loads the config anew, the one returned by [Loader.Get] is replaced on success only.
+// Values set with [viper.Viper.Set] are not read again, unlike [Secrets].
+func (l *Loader[C]) Reload() (C, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if err := l.load(); err != nil {
+		return *new(C), fmt.Errorf("reload config: %w", err)
+	}
+
+	return l.config, nil
+}
+
+// Watch reloads the config once the [ConfigMapDir] changes, calling fn with the result, until the context is done.
+// A failed reload is retried every interval until the config is fixed, see [WatchConfigMapDir].
+func (l *Loader[C]) Watch(ctx context.Context, interval time.Duration, fn func(C, error)) error {
+	if l.opts.dir == "" {
+		return fmt.Errorf("%w: no config map dir", ErrNotWatchable)
+	}
+
+	return WatchConfigMapDir(ctx, l.opts.dir, interval, l.reloader(fn))
+}
+
+func (l *Loader[C]) reloader(fn func(C, error)) func() error {
+	return func() error {
+		c, err := l.Reload()
+		fn(c, err)
+
+		return err
+	}
+}
+
 func (l *Loader[C]) load() error {
 	var config C
 
@@ -94,12 +149,48 @@ func (l *Loader[C]) load() error {
 		return fmt.Errorf("before read: %w", err)
 	}
 
-	if err := l.viper.ReadInConfig(); err != nil {
+	if err := SetDefaults(l.viper, &config); err != nil {
+		return fmt.Errorf("set defaults: %w", err)
+	}
+
+	if l.opts.envOnly {
+		if err := BindEnv(l.viper, &config); err != nil {
+			return fmt.Errorf("bind env: %w", err)
+		}
+	}
+
+	if l.opts.flags != nil {
+		if err := BindFlags(l.viper, l.opts.flags, &config); err != nil {
+			return fmt.Errorf("bind flags: %w", err)
+		}
+	}
+
+	if err := l.read(); err != nil {
 		return fmt.Errorf("read: %w", err)
 	}
 
-	if err := l.viper.Unmarshal(&config); err != nil {
-		return fmt.Errorf("unmarshal exact: %w", err)
+	src := l.viper
+
+	// the secrets are kept out of the viper of the loader, so Reload reads them anew
+	if l.opts.secrets {
+		settings, err := ResolveSecrets(l.viper, &config)
+		if err != nil {
+			return fmt.Errorf("resolve secrets: %w", err)
+		}
+
+		src = viper.New()
+
+		if err := src.MergeConfigMap(settings); err != nil {
+			return fmt.Errorf("resolve secrets: %w", err)
+		}
+	}
+
+	if err := l.unmarshal(src, &config); err != nil {
+		return fmt.Errorf("unmarshal: %w", err)
+	}
+
+	if err := Validate(config); err != nil {
+		return fmt.Errorf("validate: %w", err)
 	}
 
 	if err := config.AfterRead(l.viper); err != nil {
@@ -111,3 +202,82 @@ func (l *Loader[C]) load() error {
 
 	return nil
 }
+
+func (l *Loader[C]) read() error {
+	if l.opts.dir != "" {
+		if l.opts.layered {
+			return fmt.Errorf("%w: layered config map dir", ErrIncompatibleOptions)
+		}
+
+		return ReadConfigMapDir(l.viper, l.opts.dir)
+	}
+
+	if err := l.viper.ReadInConfig(); err != nil && (!l.opts.envOnly || !isConfigNotFound(err)) {
+		return err
+	}
+
+	if l.opts.layered {
+		return l.readOverlays()
+	}
+
+	return nil
+}
+
+// readOverlays merges the files of [Layered] next to the config file, if it's found.
+func (l *Loader[C]) readOverlays() error {
+	base := l.viper.ConfigFileUsed()
+	if base == "" {
+		return nil
+	}
+
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	overlays := make([]string, 0, 2)
+
+	if env := l.viper.GetString(layerEnvKey); env != "" {
+		overlays = append(overlays, stem+"."+env+ext)
+	}
+
+	overlays = append(overlays, stem+".local"+ext)
+
+	for _, file := range overlays {
+		b, err := os.ReadFile(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("read overlay: %w", err)
+		}
+
+		if err := l.viper.MergeConfig(bytes.NewReader(b)); err != nil {
+			return fmt.Errorf("merge %s: %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+func isConfigNotFound(err error) bool {
+	var notFound viper.ConfigFileNotFoundError
+	return errors.As(err, &notFound) || errors.Is(err, fs.ErrNotExist)
+}
+
+// unmarshal decodes the config, in strict mode the keys left unused must be allowed.
+func (l *Loader[C]) unmarshal(v *viper.Viper, config *C) error {
+	if !l.opts.strict {
+		return v.Unmarshal(config)
+	}
+
+	var md mapstructure.Metadata
+
+	if err := v.Unmarshal(config, func(dc *mapstructure.DecoderConfig) { dc.Metadata = &md }); err != nil {
+		return err
+	}
+
+	unknown := slices.DeleteFunc(md.Unused, l.opts.strictAllowed)
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		return fmt.Errorf("%w: %s", ErrUnknownKeys, strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package tcfg
+
+import (
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+type LoaderOption func(o *loaderOptions)
+
+type loaderOptions struct {
+	strict      bool
+	strictAllow []string
+	envOnly     bool
+	secrets     bool
+	dir         string
+	layered     bool
+	flags       *pflag.FlagSet
+}
+
+// Strict makes the loader fail on config keys not decoded into the config, which are typos most of the time.
+// Keys owned by other components are allowed by listing them or their parent keys, case-insensitively.
+func Strict(allow ...string) LoaderOption {
+	return func(o *loaderOptions) {
+		o.strict = true
+
+		for _, key := range allow {
+			o.strictAllow = append(o.strictAllow, strings.ToLower(key))
+		}
+	}
+}
+
+// EnvOnly binds every key of the config to its `CFG_...` variable, see [BindEnv], and makes the config file optional.
+// A file is still read if found, with the variables taking precedence.
+func EnvOnly() LoaderOption {
+	return func(o *loaderOptions) {
+		o.envOnly = true
+	}
+}
+
+// Secrets resolves the values stored in files and variables, see [ResolveSecrets].
+// It's opt-in since `file://` is a valid prefix of some values, like URLs of local storages.
+func Secrets() LoaderOption {
+	return func(o *loaderOptions) {
+		o.secrets = true
+	}
+}
+
+// ConfigMapDir reads the config from the directory instead of the config file, see [ReadConfigMapDir].
+// Use [Loader.Watch] to reload it on changes.
+func ConfigMapDir(dir string) LoaderOption {
+	return func(o *loaderOptions) {
+		o.dir = dir
+	}
+}
+
+// Layered merges the overlays of the config file over it, in order:
+//   - `config.<env>.yaml`, with the env of the `app.env` key, i.e. from `CFG_APP_ENV`, the config file or the default;
+//   - `config.local.yaml`, meant to stay out of the version control.
+//
+// The overlays are named after the config file and are optional. Maps are merged key by key, at any depth,
+// while the other values, lists included, are replaced as a whole. Variables and [Flags] take precedence over all the files.
+// It's incompatible with [ConfigMapDir], where the fragments are merged in the order of their names instead.
+func Layered() LoaderOption {
+	return func(o *loaderOptions) {
+		o.layered = true
+	}
+}
+
+// Flags binds the flags to the config keys named after them, e.g. `--log.format`, taking precedence over the variables.
+// Flags not set on the command line don't override anything, their defaults are used only if no source has the key.
+// Flags not named after a config key are skipped, see [BindFlags].
+func Flags(flags *pflag.FlagSet) LoaderOption {
+	return func(o *loaderOptions) {
+		o.flags = flags
+	}
+}
+
+func (o *loaderOptions) strictAllowed(key string) bool {
+	key = strings.ToLower(key)
+
+	for _, allowed := range o.strictAllow {
+		if key == allowed || strings.HasPrefix(key, allowed+".") || strings.HasPrefix(key, allowed+"[") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,9 +1,15 @@
 package tcfg
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewLoader(t *testing.T) {
@@ -19,3 +25,117 @@ func TestNewDefaultLoader(t *testing.T) {
 	l := NewDefaultLoader[BaseConfig]()
 	assert.NotNil(t, l.viper)
 }
+
+func TestLoader_Strict(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("app:\n  name: test\n  key: 0123456789abcdef0123456789abcdef\nlogLvl: debug\nplugin:\n  enabled: true\n"), 0o600))
+
+	newViper := func() *viper.Viper {
+		v := viper.New()
+		v.SetConfigFile(file)
+
+		return v
+	}
+
+	_, err := NewLoader[BaseConfig](newViper()).Get()
+	require.NoError(t, err)
+
+	_, err = NewLoader[BaseConfig](newViper(), Strict()).Get()
+	require.ErrorIs(t, err, ErrUnknownKeys)
+	assert.ErrorContains(t, err, "loglvl, plugin")
+
+	_, err = NewLoader[BaseConfig](newViper(), Strict("logLvl", "Plugin")).Get()
+	require.NoError(t, err)
+}
+
+func TestLoader_EnvOnly(t *testing.T) {
+	t.Setenv("CFG_APP_NAME", "test")
+	t.Setenv("CFG_APP_KEY", "0123456789abcdef0123456789abcdef")
+	t.Setenv("CFG_LOG_DEDUP_WINDOW", "5")
+
+	newViper := func() *viper.Viper {
+		v := viper.New()
+		v.AddConfigPath(t.TempDir())
+		v.SetConfigType("yaml")
+		v.AutomaticEnv()
+		v.SetEnvPrefix(envPrefix)
+		v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+		return v
+	}
+
+	_, err := NewLoader[BaseConfig](newViper()).Get()
+	require.Error(t, err)
+
+	c, err := NewLoader[BaseConfig](newViper(), EnvOnly()).Get()
+	require.NoError(t, err)
+	assert.Equal(t, "test", c.App.Name)
+	assert.Equal(t, 5, c.Log.Dedup.Window)
+	assert.Equal(t, EnvDev, c.App.Env)
+
+	v := newViper()
+	v.SetConfigFile(filepath.Join(t.TempDir(), "missing.yaml"))
+
+	_, err = NewLoader[BaseConfig](v, EnvOnly()).Get()
+	require.NoError(t, err)
+}
+
+func TestLoader_Layered(t *testing.T) {
+	t.Setenv("CFG_LOG_FORMAT", "logfmt")
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"config.yaml":       "app:\n  name: base\n  env: test\n  key: 0123456789abcdef0123456789abcdef\nlog:\n  output: [stderr, stdout]\n  format: json\n  rateLimit:\n    rate: 10\n    burst: 20\n",
+		"config.test.yaml":  "app:\n  name: test\nlog:\n  output: [stdout]\n  rateLimit:\n    rate: 5\n",
+		"config.local.yaml": "app:\n  version: local\n",
+		"config.prod.yaml":  "app:\n  name: prod\n",
+	}
+
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("app.version", "", "")
+	flags.String("report.dsn", "", "")
+	require.NoError(t, flags.Parse([]string{"--app.version=flag"}))
+
+	v := viper.New()
+	v.SetConfigFile(filepath.Join(dir, "config.yaml"))
+	v.AutomaticEnv()
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	c, err := NewLoader[BaseConfig](v, Layered(), Flags(flags)).Get()
+	require.NoError(t, err)
+	assert.Equal(t, "test", c.App.Name)
+	assert.Equal(t, "flag", c.App.Version)
+	assert.Equal(t, []string{"stdout"}, c.Log.Output)
+	assert.Equal(t, LogFormatLogfmt, c.Log.Format)
+	assert.Equal(t, LogRateLimit{Rate: 5, Burst: 20}, c.Log.RateLimit)
+	assert.Empty(t, c.Report.DSN)
+}
+
+func TestLoader_StrictFlags(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("app:\n  key: 0123456789abcdef0123456789abcdef\n"), 0o600))
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.Bool("verbose", false, "")
+	flags.String("log.format", "", "")
+	flags.String("log.fields.region", "", "")
+	require.NoError(t, flags.Parse([]string{"--verbose", "--log.format=json", "--log.fields.region=eu"}))
+
+	v := viper.New()
+	v.SetConfigFile(file)
+
+	c, err := NewLoader[BaseConfig](v, Strict(), Flags(flags)).Get()
+	require.NoError(t, err)
+	assert.Equal(t, LogFormatJSON, c.Log.Format)
+	assert.Equal(t, map[string]any{"region": "eu"}, c.Log.Fields)
+	assert.False(t, v.IsSet("verbose"))
+}
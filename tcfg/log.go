@@ -0,0 +1,128 @@
+package tcfg
+
+import "time"
+
+type LogFormat string
+
+const (
+	LogFormatConsole LogFormat = "console"
+	LogFormatJSON    LogFormat = "json"
+	LogFormatLogfmt  LogFormat = "logfmt"
+	LogFormatPretty  LogFormat = "pretty" // falls back to console unless all outputs are terminals
+)
+
+func (f LogFormat) String() string {
+	return string(f)
+}
+
+const (
+	bytesInKilobyte = 1 << 10
+	bytesInMegabyte = 1 << 20
+)
+
+const (
+	LogOutputStdout = "stdout"
+	LogOutputStderr = "stderr"
+)
+
+// Log describes how the application logger is built.
+//
+// `Output` accepts "stdout", "stderr" and file paths, several at once.
+// `Caller` and `Stacktrace` are level thresholds: entries at or above them get a caller or a stacktrace attached,
+// empty value disables the feature.
+type Log struct {
+	Format     LogFormat      `mapstructure:"format" json:"format" yaml:"format" validate:"omitempty,oneof=console json logfmt pretty"`
+	Output     []string       `mapstructure:"output" json:"output" yaml:"output"`
+	Sampling   LogSampling    `mapstructure:"sampling" json:"sampling" yaml:"sampling"`
+	Caller     string         `mapstructure:"caller" json:"caller" yaml:"caller"`
+	Stacktrace string         `mapstructure:"stacktrace" json:"stacktrace" yaml:"stacktrace"`
+	Fields     map[string]any `mapstructure:"fields" json:"fields" yaml:"fields"`
+	File       LogFile        `mapstructure:"file" json:"file" yaml:"file"`
+	Buffer     LogBuffer      `mapstructure:"buffer" json:"buffer" yaml:"buffer"`
+	Dedup      LogDedup       `mapstructure:"dedup" json:"dedup" yaml:"dedup"`
+	RateLimit  LogRateLimit   `mapstructure:"rateLimit" json:"rateLimit" yaml:"rateLimit"`
+}
+
+func (l *Log) Defaults() {
+	l.Output = []string{AppLogOutputDefault}
+}
+
+// LogSampling limits the number of identical entries logged per second:
+// the first `Initial` entries are logged, then every `Thereafter`-th one.
+// Zero `Initial` disables sampling.
+type LogSampling struct {
+	Initial    int `mapstructure:"initial" json:"initial" yaml:"initial"`
+	Thereafter int `mapstructure:"thereafter" json:"thereafter" yaml:"thereafter"`
+}
+
+// LogFile configures rotation of file outputs.
+// Rotated files are kept next to the original one, zero values disable the corresponding feature.
+type LogFile struct {
+	RotateSize     int  `mapstructure:"rotateSize" json:"rotateSize" yaml:"rotateSize"`             // megabytes
+	RotateInterval int  `mapstructure:"rotateInterval" json:"rotateInterval" yaml:"rotateInterval"` // seconds
+	MaxBackups     int  `mapstructure:"maxBackups" json:"maxBackups" yaml:"maxBackups"`
+	Compress       bool `mapstructure:"compress" json:"compress" yaml:"compress"`
+}
+
+func (f LogFile) RotateSizeBytes() int64 {
+	if f.RotateSize < 1 {
+		return 0
+	}
+
+	return int64(f.RotateSize) * bytesInMegabyte
+}
+
+func (f LogFile) RotateIntervalSeconds() time.Duration {
+	if f.RotateInterval < 1 {
+		return 0
+	}
+
+	return time.Duration(f.RotateInterval) * time.Second
+}
+
+// LogBuffer enables in-memory buffering of the outputs, zero `Size` disables it.
+// With `Async`, entries are written by a background goroutine and a full buffer either blocks the logging one
+// or makes it drop the entry if `Drop` is set.
+type LogBuffer struct {
+	Size          int  `mapstructure:"size" json:"size" yaml:"size"`                            // kilobytes
+	FlushInterval int  `mapstructure:"flushInterval" json:"flushInterval" yaml:"flushInterval"` // seconds
+	Async         bool `mapstructure:"async" json:"async" yaml:"async"`
+	Drop          bool `mapstructure:"drop" json:"drop" yaml:"drop"`
+}
+
+func (b LogBuffer) SizeBytes() int {
+	if b.Size < 1 {
+		return 0
+	}
+
+	return b.Size * bytesInKilobyte
+}
+
+func (b LogBuffer) FlushIntervalSeconds() time.Duration {
+	if b.FlushInterval < 1 {
+		return 0
+	}
+
+	return time.Duration(b.FlushInterval) * time.Second
+}
+
+// LogDedup folds identical entries (same level, logger, message and error) logged within `Window`
+// into the first one followed by a summary with the number of repetitions. Zero `Window` disables it.
+type LogDedup struct {
+	Window int `mapstructure:"window" json:"window" yaml:"window"` // seconds
+}
+
+func (d LogDedup) WindowSeconds() time.Duration {
+	if d.Window < 1 {
+		return 0
+	}
+
+	return time.Duration(d.Window) * time.Second
+}
+
+// LogRateLimit limits the number of entries per second of each named logger with a token bucket
+// of `Burst` tokens (`Rate` if zero). Zero `Rate` disables it.
+type LogRateLimit struct {
+	Rate  int `mapstructure:"rate" json:"rate" yaml:"rate"`
+	Burst int `mapstructure:"burst" json:"burst" yaml:"burst"`
+}
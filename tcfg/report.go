@@ -0,0 +1,27 @@
+package tcfg
+
+import "time"
+
+// Report configures sending of error reports, empty `DSN` disables it.
+//
+// `DSN` is a Sentry-compatible one: `https://<key>@<host>/<project>`.
+// Entries at or above the `Level` are reported, they are sent in batches of up to `BatchSize`
+// every `FlushInterval` seconds.
+type Report struct {
+	DSN           string `mapstructure:"dsn" json:"dsn" yaml:"dsn"`
+	Level         string `mapstructure:"level" json:"level" yaml:"level"`
+	BatchSize     int    `mapstructure:"batchSize" json:"batchSize" yaml:"batchSize"`
+	FlushInterval int    `mapstructure:"flushInterval" json:"flushInterval" yaml:"flushInterval"` // seconds
+}
+
+func (r *Report) Defaults() {
+	r.Level = AppReportLevelDefault.String()
+}
+
+func (r Report) FlushIntervalSeconds() time.Duration {
+	if r.FlushInterval < 1 {
+		return 0
+	}
+
+	return time.Duration(r.FlushInterval) * time.Second
+}
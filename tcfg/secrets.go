@@ -0,0 +1,117 @@
+package tcfg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	refSchemeFile = "file://"
+	refSchemeEnv  = "env://"
+)
+
+var (
+	ErrUnresolvedRef = errors.New("unresolved reference")
+)
+
+// ResolveSecrets returns the settings of viper with the values of the config keys stored elsewhere,
+// which is how secrets are usually mounted:
+//   - from the file named by the `CFG_<KEY>_FILE` variable, e.g. `CFG_DB_DSN_FILE=/run/secrets/dsn`;
+//   - from the file or the variable referenced by a string value, `file:///run/secrets/dsn` or `env://DB_DSN`.
+//
+// Trailing newlines of the files are trimmed. A `_FILE` variable takes precedence over the value of the key.
+// Viper is left as is, so each call reads the secrets anew, e.g. once they are rotated.
+func ResolveSecrets(v *viper.Viper, config any) (map[string]any, error) {
+	t := reflect.TypeOf(config)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	settings := v.AllSettings()
+	resolved := make(map[string]bool)
+
+	var errs []error
+
+	if t.Kind() == reflect.Struct {
+		walkLeaves(reflect.New(t).Elem(), "", func(key string, _ reflect.Value) {
+			file, ok := os.LookupEnv(envName(key, true) + envSuffixFile)
+			if !ok {
+				return
+			}
+
+			value, err := readSecretFile(file)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+
+			setSetting(settings, strings.ToLower(key), value)
+			resolved[strings.ToLower(key)] = true
+		})
+	}
+
+	for _, key := range v.AllKeys() {
+		ref, ok := v.Get(key).(string)
+		if !ok || resolved[key] {
+			continue
+		}
+
+		value, ok, err := resolveRef(ref)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		} else if ok {
+			setSetting(settings, key, value)
+		}
+	}
+
+	return settings, errors.Join(errs...)
+}
+
+// setSetting sets the value at the dot-separated key of the nested settings map.
+func setSetting(settings map[string]any, key string, value any) {
+	parts := strings.Split(key, ".")
+
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := settings[part].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			settings[part] = next
+		}
+
+		settings = next
+	}
+
+	settings[parts[len(parts)-1]] = value
+}
+
+func resolveRef(ref string) (string, bool, error) {
+	if file, ok := strings.CutPrefix(ref, refSchemeFile); ok {
+		value, err := readSecretFile(file)
+		return value, true, err
+	}
+
+	if name, ok := strings.CutPrefix(ref, refSchemeEnv); ok {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", true, fmt.Errorf("%w: %s is not set", ErrUnresolvedRef, name)
+		}
+
+		return value, true, nil
+	}
+
+	return "", false, nil
+}
+
+func readSecretFile(name string) (string, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrUnresolvedRef, err)
+	}
+
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
@@ -0,0 +1,74 @@
+package tcfg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSecrets(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	dsnFile := filepath.Join(dir, "dsn")
+
+	require.NoError(t, os.WriteFile(keyFile, []byte("0123456789abcdef0123456789abcdef\n"), 0o600))
+	require.NoError(t, os.WriteFile(dsnFile, []byte("https://key@sentry.example.com/1\n"), 0o600))
+
+	t.Setenv("CFG_APP_KEY_FILE", keyFile)
+	t.Setenv("TEST_APP_NAME", "test")
+
+	v := viper.New()
+	v.Set("app.key", "overridden")
+	v.Set("app.name", "env://TEST_APP_NAME")
+	v.Set("report.dsn", "file://"+dsnFile)
+	v.Set("log.output", "stderr")
+
+	settings, err := ResolveSecrets(v, &BaseConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"app":    map[string]any{"key": "0123456789abcdef0123456789abcdef", "name": "test"},
+		"report": map[string]any{"dsn": "https://key@sentry.example.com/1"},
+		"log":    map[string]any{"output": "stderr"},
+	}, settings)
+	assert.Equal(t, "env://TEST_APP_NAME", v.GetString("app.name"))
+
+	v.Set("app.name", "env://TEST_MISSING")
+	v.Set("report.dsn", "file://"+filepath.Join(dir, "missing"))
+
+	_, err = ResolveSecrets(v, &BaseConfig{})
+	require.ErrorIs(t, err, ErrUnresolvedRef)
+	assert.ErrorContains(t, err, "app.name")
+	assert.ErrorContains(t, err, "report.dsn")
+}
+
+func TestLoader_Secrets(t *testing.T) {
+	dir := t.TempDir()
+	nameFile := filepath.Join(dir, "name")
+	configFile := filepath.Join(dir, "config.yaml")
+
+	require.NoError(t, os.WriteFile(nameFile, []byte("v1\n"), 0o600))
+	require.NoError(t, os.WriteFile(configFile, []byte("app:\n  name: file://"+nameFile+"\n"), 0o600))
+
+	t.Setenv("CFG_APP_KEY_FILE", filepath.Join(dir, "key"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "key"), []byte("0123456789abcdef0123456789abcdef\n"), 0o600))
+
+	v := viper.New()
+	v.SetConfigFile(configFile)
+
+	l := NewLoader[BaseConfig](v, Secrets(), Strict())
+
+	c, err := l.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "v1", c.App.Name)
+	assert.Equal(t, Key("0123456789abcdef0123456789abcdef"), c.App.Key)
+
+	require.NoError(t, os.WriteFile(nameFile, []byte("v2\n"), 0o600))
+
+	c, err = l.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, "v2", c.App.Name)
+}
@@ -0,0 +1,214 @@
+package tcfg
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	validateTag     = "validate"
+	mapstructureTag = "mapstructure"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid config")
+)
+
+// ValidationError is a violated `validate` rule of the config field.
+type ValidationError struct {
+	Path string // dot-separated mapstructure keys, `[i]` for list items
+	Env  string // the variable overriding the field, empty for list items
+	Rule string // with the parameter, e.g. `min=1`
+}
+
+func (e *ValidationError) Error() string {
+	if e.Env == "" {
+		return e.Path + ": " + e.Rule
+	}
+
+	return e.Path + " (" + e.Env + "): " + e.Rule
+}
+
+// ValidationErrors holds all the violations found by [Validate].
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+func (ValidationErrors) Is(target error) bool {
+	return target == ErrInvalidConfig
+}
+
+// Validate checks the `validate` tags of the config tree and returns [ValidationErrors] listing every violation.
+//
+// Rules are comma-separated:
+//   - `required`: the value is not zero, lists and maps are not empty;
+//   - `omitempty`: skip the other rules if the value is zero;
+//   - `min=N`, `max=N`: bounds of numbers, or of the length of strings, lists and maps;
+//   - `oneof=a b c`: the string or number is one of the space-separated values.
+//
+// Nested structs, pointers to them and list items are validated as well.
+func Validate(config any) error {
+	var errs ValidationErrors
+
+	validateValue(reflect.ValueOf(config), "", true, &errs)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func validateValue(v reflect.Value, path string, env bool, errs *ValidationErrors) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		validateStruct(v, path, env, errs)
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			validateValue(v.Index(i), path+"["+strconv.Itoa(i)+"]", false, errs)
+		}
+	}
+}
+
+func validateStruct(v reflect.Value, path string, env bool, errs *ValidationErrors) {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldPath, ok := fieldKey(field, path)
+		if !ok {
+			continue
+		}
+
+		fv := v.Field(i)
+
+		if rules := field.Tag.Get(validateTag); rules != "" {
+			validateRules(fv, rules, &ValidationError{Path: fieldPath, Env: envName(fieldPath, env)}, errs)
+		}
+
+		validateValue(fv, fieldPath, env, errs)
+	}
+}
+
+func validateRules(v reflect.Value, rules string, base *ValidationError, errs *ValidationErrors) {
+	for rule := range strings.SplitSeq(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		var ok bool
+
+		switch name {
+		case "omitempty":
+			if v.IsZero() {
+				return
+			}
+
+			continue
+		case "required":
+			ok = !v.IsZero() && (!hasLen(v) || v.Len() > 0)
+		case "min", "max":
+			ok = validateBound(v, name, param)
+		case "oneof":
+			ok = slices.Contains(strings.Fields(param), valueString(v))
+		default:
+			rule = fmt.Sprintf("unknown rule %q", rule)
+		}
+
+		if !ok {
+			err := *base
+			err.Rule = rule
+			*errs = append(*errs, &err)
+		}
+	}
+}
+
+func validateBound(v reflect.Value, name, param string) bool {
+	bound, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return false
+	}
+
+	var n float64
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	default:
+		if !hasLen(v) {
+			return false
+		}
+
+		n = float64(v.Len())
+	}
+
+	if name == "min" {
+		return n >= bound
+	}
+
+	return n <= bound
+}
+
+func hasLen(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	default:
+		return false
+	}
+}
+
+func valueString(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+func joinKey(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+// envName returns the variable name the default loader binds to the key.
+func envName(path string, env bool) string {
+	if !env {
+		return ""
+	}
+
+	return envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(path, ".", "_"))
+}
@@ -0,0 +1,57 @@
+package tcfg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testValidateConfig struct {
+	BaseConfig `mapstructure:",squash"` //nolint:tagliatelle // test
+
+	DB struct {
+		DSN      string `mapstructure:"dsn" validate:"required"`
+		PoolSize int    `mapstructure:"poolSize" validate:"min=1,max=100"`
+	} `mapstructure:"db"`
+	Mode    string `mapstructure:"mode" validate:"omitempty,oneof=fast safe"`
+	Servers []struct {
+		Host string `mapstructure:"host" validate:"required"`
+	} `mapstructure:"servers" validate:"min=1"`
+	Skipped string `mapstructure:"-" validate:"required"`
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	var c testValidateConfig
+	c.DB.PoolSize = 101
+	c.Mode = "slow"
+	c.Log.Format = "xml"
+
+	err := Validate(c)
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	assert.Equal(t, ""+
+		"log.format (CFG_LOG_FORMAT): oneof=console json logfmt pretty\n"+
+		"db.dsn (CFG_DB_DSN): required\n"+
+		"db.poolSize (CFG_DB_POOLSIZE): max=100\n"+
+		"mode (CFG_MODE): oneof=fast safe\n"+
+		"servers (CFG_SERVERS): min=1",
+		err.Error(),
+	)
+
+	c = testValidateConfig{}
+	c.DB.DSN = "postgres://"
+	c.DB.PoolSize = 10
+	c.Servers = append(c.Servers, struct {
+		Host string `mapstructure:"host" validate:"required"`
+	}{})
+
+	var verrs ValidationErrors
+	require.ErrorAs(t, Validate(&c), &verrs)
+	require.Len(t, verrs, 1)
+	assert.Equal(t, "servers[0].host: required", verrs[0].Error())
+
+	c.Servers[0].Host = "localhost"
+	require.NoError(t, Validate(&c))
+}
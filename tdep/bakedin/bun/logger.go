@@ -0,0 +1,74 @@
+package tdep_bun
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	pgLogName  = "pgdriver"
+	bunLogName = "bun"
+)
+
+var (
+	pgLog atomic.Pointer[zap.Logger]
+)
+
+// The pgdriver and bun loggers are package variables without a lock, so they're replaced before any DB exists.
+func init() {
+	pgdriver.Logger = pgLogger{}
+	bun.SetLogger(bunLogger{})
+}
+
+// setPGLog makes the pgdriver and bun loggers write to the logger of the dep. Those are shared by all the DBs,
+// so with several deps they write to the one of the dep resolved last.
+func setPGLog(log *zap.Logger) {
+	pgLog.Store(log)
+}
+
+// pgLogger writes failed connection writes and dropped notifications at warn level, the rest at info one.
+type pgLogger struct{}
+
+func (pgLogger) Printf(_ context.Context, format string, v ...any) {
+	pgLogTo(pgLogName).Log(pgLogLevel(format, v), strings.TrimPrefix(fmt.Sprintf(format, v...), "pgdriver: "))
+}
+
+// bunLogger uses the same levels, bun logs about deprecations and misuses mostly.
+type bunLogger struct{}
+
+func (bunLogger) Printf(format string, v ...any) {
+	pgLogTo(bunLogName).Log(pgLogLevel(format, v), strings.TrimPrefix(fmt.Sprintf(format, v...), "bun: "))
+}
+
+func pgLogTo(name string) *zap.Logger {
+	if log := pgLog.Load(); log != nil {
+		return log.Named(name)
+	}
+
+	return zap.L().Named(name)
+}
+
+func pgLogLevel(format string, v []any) zapcore.Level {
+	for _, arg := range v {
+		if _, ok := arg.(error); ok {
+			return zapcore.WarnLevel
+		}
+	}
+
+	format = strings.ToLower(format)
+
+	for _, word := range []string{"fail", "drop", "discard"} {
+		if strings.Contains(format, word) {
+			return zapcore.WarnLevel
+		}
+	}
+
+	return zapcore.InfoLevel
+}
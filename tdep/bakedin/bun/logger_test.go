@@ -0,0 +1,32 @@
+package tdep_bun
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestPGLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	setPGLog(zap.New(core).Named("db"))
+	t.Cleanup(func() { pgLog.Store(nil) })
+
+	pgdriver.Logger.Printf(t.Context(), "pgdriver: Listener buffer is full (message is dropped)")
+	bunLogger{}.Printf("bun: %s has unknown tag option: %q", "Model.ID", "pk1")
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+	assert.Equal(t, "db."+pgLogName, entries[0].LoggerName)
+	assert.Equal(t, "Listener buffer is full (message is dropped)", entries[0].Message)
+
+	assert.Equal(t, zapcore.InfoLevel, entries[1].Level)
+	assert.Equal(t, "db."+bunLogName, entries[1].LoggerName)
+	assert.Equal(t, `Model.ID has unknown tag option: "pk1"`, entries[1].Message)
+}
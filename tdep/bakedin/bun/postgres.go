@@ -29,6 +29,8 @@ func NewPostgres[C IDB](
 	options ...tdep.Option,
 ) *tdep.D[C] {
 	resolve := func(o tdep.OptSet) (C, error) {
+		setPGLog(o.Log())
+
 		connOpts := []pgdriver.Option{
 			pgdriver.WithApplicationName(o.Name()),
 			pgdriver.WithDSN(cfg.DSN),
@@ -3,10 +3,12 @@ package tdep_grpc
 import (
 	"strconv"
 
-	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
+	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 
 	"github.com/heffcodex/the/tdep"
+	"github.com/heffcodex/the/tzap"
 )
 
 type ClientConfig struct {
@@ -16,16 +18,18 @@ type ClientConfig struct {
 
 func NewClient[C grpc.ClientConnInterface](cfg ClientConfig, dialOptions []grpc.DialOption, options ...tdep.Option) *tdep.D[C] {
 	resolve := func(o tdep.OptSet) (C, error) {
+		setGRPCLog(o.Log())
+
 		target := cfg.Host + ":" + strconv.FormatInt(int64(cfg.Port), 10)
 
-		logDecider := grpc_zap.WithDecider(func(_ string, err error) bool { return o.IsDebug() || err != nil })
-		unaryLog := grpc_zap.UnaryClientInterceptor(o.Log(), logDecider)
-		streamLog := grpc_zap.StreamClientInterceptor(o.Log(), logDecider)
+		logOptions := tzap.GRPCLogOptions{Level: clientCodeLevel}
+		unaryLog := tzap.GRPCUnaryClientLog(o.Log(), logOptions)
+		streamLog := tzap.GRPCStreamClientLog(o.Log(), logOptions)
 
 		dialOptions = append(dialOptions,
 			grpc.WithUserAgent(o.Name()),
-			grpc.WithUnaryInterceptor(unaryLog),
-			grpc.WithStreamInterceptor(streamLog),
+			grpc.WithChainUnaryInterceptor(tzap.GRPCUnaryClientContext(), unaryLog),
+			grpc.WithChainStreamInterceptor(tzap.GRPCStreamClientContext(), streamLog),
 		)
 
 		client, err := grpc.NewClient(target, dialOptions...)
@@ -38,3 +42,12 @@ func NewClient[C grpc.ClientConnInterface](cfg ClientConfig, dialOptions []grpc.
 
 	return tdep.New(resolve, options...)
 }
+
+// clientCodeLevel logs successful calls in debug mode only.
+func clientCodeLevel(code codes.Code) zapcore.Level {
+	if code == codes.OK {
+		return zapcore.DebugLevel
+	}
+
+	return tzap.GRPCCodeLevel(code)
+}
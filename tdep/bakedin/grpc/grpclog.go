@@ -0,0 +1,132 @@
+package tdep_grpc
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/grpclog"
+)
+
+const (
+	grpcLogName       = "grpclog"
+	grpcLogCallerSkip = 3 // grpcLogger.write, the grpcLogger method and the grpclog function calling it
+)
+
+var (
+	grpcLog atomic.Pointer[zap.Logger]
+
+	_ grpclog.DepthLoggerV2 = grpcLogger{}
+)
+
+// [grpclog.SetLoggerV2] isn't mutex-protected and must be called before any gRPC use, so it's done on import.
+func init() {
+	grpclog.SetLoggerV2(grpcLogger{})
+}
+
+// setGRPCLog makes the gRPC logger write to the logger of the dep. Being process-wide, it follows the client
+// resolved last, and the global zap logger until there's one.
+func setGRPCLog(log *zap.Logger) {
+	grpcLog.Store(log.Named(grpcLogName))
+}
+
+func grpcLogTo() *zap.Logger {
+	if log := grpcLog.Load(); log != nil {
+		return log
+	}
+
+	return zap.L().Named(grpcLogName)
+}
+
+// grpcLogger writes gRPC info logs at debug level since they're mostly about connectivity state changes.
+// The caller is the one of the gRPC code, the depth methods are used by gRPC itself.
+type grpcLogger struct{}
+
+func (l grpcLogger) Info(args ...any) {
+	l.write(0, zapcore.DebugLevel, fmt.Sprint(args...))
+}
+
+func (l grpcLogger) Infoln(args ...any) {
+	l.write(0, zapcore.DebugLevel, sprintln(args...))
+}
+
+func (l grpcLogger) Infof(format string, args ...any) {
+	l.write(0, zapcore.DebugLevel, fmt.Sprintf(format, args...))
+}
+
+func (l grpcLogger) InfoDepth(depth int, args ...any) {
+	l.write(depth, zapcore.DebugLevel, sprintln(args...))
+}
+
+func (l grpcLogger) Warning(args ...any) {
+	l.write(0, zapcore.WarnLevel, fmt.Sprint(args...))
+}
+
+func (l grpcLogger) Warningln(args ...any) {
+	l.write(0, zapcore.WarnLevel, sprintln(args...))
+}
+
+func (l grpcLogger) Warningf(format string, args ...any) {
+	l.write(0, zapcore.WarnLevel, fmt.Sprintf(format, args...))
+}
+
+func (l grpcLogger) WarningDepth(depth int, args ...any) {
+	l.write(depth, zapcore.WarnLevel, sprintln(args...))
+}
+
+func (l grpcLogger) Error(args ...any) {
+	l.write(0, zapcore.ErrorLevel, fmt.Sprint(args...))
+}
+
+func (l grpcLogger) Errorln(args ...any) {
+	l.write(0, zapcore.ErrorLevel, sprintln(args...))
+}
+
+func (l grpcLogger) Errorf(format string, args ...any) {
+	l.write(0, zapcore.ErrorLevel, fmt.Sprintf(format, args...))
+}
+
+func (l grpcLogger) ErrorDepth(depth int, args ...any) {
+	l.write(depth, zapcore.ErrorLevel, sprintln(args...))
+}
+
+func (l grpcLogger) Fatal(args ...any) {
+	l.write(0, zapcore.FatalLevel, fmt.Sprint(args...))
+}
+
+func (l grpcLogger) Fatalln(args ...any) {
+	l.write(0, zapcore.FatalLevel, sprintln(args...))
+}
+
+func (l grpcLogger) Fatalf(format string, args ...any) {
+	l.write(0, zapcore.FatalLevel, fmt.Sprintf(format, args...))
+}
+
+func (l grpcLogger) FatalDepth(depth int, args ...any) {
+	l.write(depth, zapcore.FatalLevel, sprintln(args...))
+}
+
+// V reports whether verbose logging is enabled, gRPC verbosity is bound to the debug level.
+func (grpcLogger) V(level int) bool {
+	return level <= 0 || grpcLogTo().Core().Enabled(zapcore.DebugLevel)
+}
+
+// write logs with the caller `depth` frames above the gRPC logging function.
+func (grpcLogger) write(depth int, level zapcore.Level, msg string) {
+	log := grpcLogTo()
+	if !log.Core().Enabled(level) {
+		return
+	}
+
+	log = log.WithOptions(zap.AddCallerSkip(grpcLogCallerSkip + depth))
+
+	if ce := log.Check(level, msg); ce != nil {
+		ce.Write()
+	}
+}
+
+func sprintln(args ...any) string {
+	msg := fmt.Sprintln(args...)
+	return msg[:len(msg)-1]
+}
@@ -0,0 +1,34 @@
+package tdep_grpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc/grpclog"
+)
+
+func TestGRPCLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	setGRPCLog(zap.New(core, zap.AddCaller()).Named("api"))
+	t.Cleanup(func() { grpcLog.Store(nil) })
+
+	grpclog.Component("test").Infof("state %s", "READY")
+	grpclog.Warningf("deprecated %d", 1)
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+
+	for _, e := range entries {
+		assert.Equal(t, "api."+grpcLogName, e.LoggerName)
+		assert.Contains(t, e.Caller.Function, "TestGRPCLogger")
+	}
+
+	assert.Equal(t, zapcore.DebugLevel, entries[0].Level)
+	assert.Equal(t, "[test] state READY", entries[0].Message)
+	assert.Equal(t, zapcore.WarnLevel, entries[1].Level)
+	assert.Equal(t, "deprecated 1", entries[1].Message)
+}
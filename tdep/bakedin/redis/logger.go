@@ -0,0 +1,61 @@
+package tdep_redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	redisLogName = "redis"
+)
+
+var (
+	redisLog atomic.Pointer[zap.Logger]
+)
+
+// The go-redis logger is a package variable without a lock, so it's replaced before any client exists.
+func init() {
+	redis.SetLogger(redisLogger{})
+}
+
+// setRedisLog makes the go-redis logger write to the logger of the dep.
+// go-redis has a single logger for all the clients, the dep resolved last gets its output.
+func setRedisLog(log *zap.Logger) {
+	redisLog.Store(log.Named(redisLogName))
+}
+
+// redisLogger writes failures go-redis recovers from at warn level, the other messages like fallbacks at info one.
+type redisLogger struct{}
+
+func (redisLogger) Printf(_ context.Context, format string, v ...any) {
+	log := redisLog.Load()
+	if log == nil {
+		log = zap.L().Named(redisLogName)
+	}
+
+	log.Log(redisLogLevel(format, v), strings.TrimPrefix(fmt.Sprintf(format, v...), "redis: "))
+}
+
+func redisLogLevel(format string, v []any) zapcore.Level {
+	for _, arg := range v {
+		if _, ok := arg.(error); ok {
+			return zapcore.WarnLevel
+		}
+	}
+
+	format = strings.ToLower(format)
+
+	for _, word := range []string{"fail", "discard"} {
+		if strings.Contains(format, word) {
+			return zapcore.WarnLevel
+		}
+	}
+
+	return zapcore.InfoLevel
+}
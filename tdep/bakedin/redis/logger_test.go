@@ -0,0 +1,31 @@
+package tdep_redis
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRedisLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	setRedisLog(zap.New(core).Named("cache"))
+	t.Cleanup(func() { redisLog.Store(nil) })
+
+	redisLogger{}.Printf(t.Context(), "redis: %s:%d: auto mode fallback", "sentinel", 1)
+	redisLogger{}.Printf(t.Context(), "redis: discarding bad PubSub connection: %s", errors.New("EOF"))
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, zapcore.InfoLevel, entries[0].Level)
+	assert.Equal(t, "cache."+redisLogName, entries[0].LoggerName)
+	assert.Equal(t, "sentinel:1: auto mode fallback", entries[0].Message)
+
+	assert.Equal(t, zapcore.WarnLevel, entries[1].Level)
+	assert.Equal(t, "discarding bad PubSub connection: EOF", entries[1].Message)
+}
@@ -12,6 +12,8 @@ import (
 
 func NewRedix[C redix.UniversalClient](config redix.Config, options ...tdep.Option) *tdep.D[C] {
 	resolve := func(o tdep.OptSet) (C, error) {
+		setRedisLog(o.Log())
+
 		_config := redix.Config{
 			Name:      config.Name,
 			Namespace: config.Namespace,
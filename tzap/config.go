@@ -0,0 +1,236 @@
+package tzap
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/heffcodex/the/tcfg"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported log format")
+)
+
+// New builds a logger as described by the `log` config section, buffered entries are flushed by [zap.Logger.Sync].
+// The returned func closes the outputs, the entries logged afterwards are written to stderr,
+// so the ones of the very end of the shutdown aren't lost.
+//
+// The `tees` cores get the entries at their levels along with the outputs, after the dedup, rate limiting
+// and sampling, e.g. to report errors no more often than they are logged.
+func New(cfg tcfg.Log, le zapcore.LevelEnabler, tees ...zapcore.Core) (*zap.Logger, func() error, error) {
+	ws, closeOutputs, err := openOutputs(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open output: %w", err)
+	}
+
+	out := &outputs{ws: ws, closers: []func() error{closeOutputs}}
+
+	var buffered *BufferedWriteSyncer
+
+	if cfg.Buffer.Size > 0 {
+		buffered = NewBufferedWriteSyncer(out.ws, BufferedConfig{
+			Size:          cfg.Buffer.SizeBytes(),
+			FlushInterval: cfg.Buffer.FlushIntervalSeconds(),
+			Async:         cfg.Buffer.Async,
+			Drop:          cfg.Buffer.Drop,
+		})
+		out.ws = buffered
+		out.closers = append(out.closers, buffered.Stop)
+	}
+
+	stdCfg := DefaultStdCoreConfig(le)
+	stdCfg.Output = out
+
+	var core zapcore.Core
+
+	switch cfg.Format {
+	case tcfg.LogFormatConsole:
+		core = stdCfg.Console()
+	case tcfg.LogFormatJSON:
+		core = stdCfg.JSON()
+	case tcfg.LogFormatLogfmt:
+		core = stdCfg.Logfmt()
+	case tcfg.LogFormatPretty:
+		if IsTerminal(cfg.Output...) {
+			core = stdCfg.Pretty()
+		} else {
+			core = stdCfg.Console()
+		}
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, cfg.Format)
+	}
+
+	// The buffered, caller, trace, tee and dedup cores add themselves on Check instead of calling the one of the wrapped core,
+	// so only the I/O core and such wrappers may be below them, while the filtering ones, like the sampler, go above.
+	if buffered != nil {
+		core = newBufferedCore(core, buffered)
+	}
+
+	var (
+		opts        []zap.Option
+		callerLevel = zapcore.FatalLevel + 1 // entries written bypassing zap.Logger (like from slog) may have it set
+	)
+
+	if cfg.Caller != "" {
+		callerLevel, err = zapcore.ParseLevel(cfg.Caller)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse caller level: %w", err)
+		}
+
+		opts = append(opts, zap.AddCaller())
+	}
+
+	core = NewTraceCore(newCallerCore(core, callerLevel))
+
+	if len(tees) > 0 {
+		core = append(levelTee{core}, tees...)
+	}
+
+	if cfg.Stacktrace != "" {
+		lvl, err := zapcore.ParseLevel(cfg.Stacktrace)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse stacktrace level: %w", err)
+		}
+
+		opts = append(opts, zap.AddStacktrace(lvl))
+	}
+
+	if window := cfg.Dedup.WindowSeconds(); window > 0 {
+		core = NewDedupCore(core, window)
+	}
+
+	if rl := cfg.RateLimit; rl.Rate > 0 {
+		core = NewRateLimitCore(core, rl.Rate, rl.Burst)
+	}
+
+	if s := cfg.Sampling; s.Initial > 0 {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, s.Initial, s.Thereafter)
+	}
+
+	if len(cfg.Fields) > 0 {
+		fields := make([]zap.Field, 0, len(cfg.Fields))
+
+		for _, k := range slices.Sorted(maps.Keys(cfg.Fields)) {
+			fields = append(fields, zap.Any(k, cfg.Fields[k]))
+		}
+
+		opts = append(opts, zap.Fields(fields...))
+	}
+
+	return zap.New(core, opts...), out.Close, nil
+}
+
+// openOutputs opens file paths as [RotatingFile]s reopened on SIGHUP,
+// anything else (stdout, stderr, registered sink URLs) is opened with [zap.Open].
+func openOutputs(cfg tcfg.Log) (zapcore.WriteSyncer, func() error, error) {
+	var (
+		paths   []string
+		writers []zapcore.WriteSyncer
+		closers []func() error
+	)
+
+	closeAll := func() error {
+		var errs error
+
+		for i := len(closers) - 1; i >= 0; i-- {
+			errs = errors.Join(errs, closers[i]())
+		}
+
+		return errs
+	}
+
+	for _, output := range cfg.Output {
+		if output == tcfg.LogOutputStdout || output == tcfg.LogOutputStderr || strings.Contains(output, "://") {
+			paths = append(paths, output)
+			continue
+		}
+
+		f, err := NewRotatingFile(RotatingFileConfig{
+			Path:           output,
+			RotateSize:     cfg.File.RotateSizeBytes(),
+			RotateInterval: cfg.File.RotateIntervalSeconds(),
+			MaxBackups:     cfg.File.MaxBackups,
+			Compress:       cfg.File.Compress,
+		})
+		if err != nil {
+			return nil, nil, errors.Join(fmt.Errorf("%s: %w", output, err), closeAll())
+		}
+
+		stop := f.ReopenOn(func(err error) { _, _ = fmt.Fprintf(os.Stderr, "reopen log file %s: %v\n", output, err) }, syscall.SIGHUP)
+		writers = append(writers, f)
+		closers = append(closers, func() error {
+			stop()
+			return f.Close()
+		})
+	}
+
+	if len(paths) > 0 || len(writers) == 0 {
+		if len(paths) == 0 {
+			paths = []string{tcfg.LogOutputStderr}
+		}
+
+		out, closeOut, err := zap.Open(paths...)
+		if err != nil {
+			return nil, nil, errors.Join(err, closeAll())
+		}
+
+		writers = append(writers, out)
+		closers = append(closers, func() error {
+			closeOut()
+			return nil
+		})
+	}
+
+	return zap.CombineWriteSyncers(writers...), closeAll, nil
+}
+
+// outputs forwards to the writers of the logger until closed, then to stderr.
+type outputs struct {
+	mu      sync.RWMutex
+	ws      zapcore.WriteSyncer
+	closers []func() error // called in reverse order
+}
+
+func (o *outputs) Write(p []byte) (int, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	return o.ws.Write(p)
+}
+
+func (o *outputs) Sync() error {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	return o.ws.Sync()
+}
+
+func (o *outputs) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closers == nil {
+		return nil
+	}
+
+	var errs error
+
+	for i := len(o.closers) - 1; i >= 0; i-- {
+		errs = errors.Join(errs, o.closers[i]())
+	}
+
+	o.ws = zapcore.Lock(os.Stderr)
+	o.closers = nil
+
+	return errs
+}
@@ -0,0 +1,57 @@
+package tzap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/heffcodex/the/tcfg"
+)
+
+func TestNew_Close(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	log, closeLog, err := New(tcfg.Log{Format: tcfg.LogFormatJSON, Output: []string{path}}, zapcore.InfoLevel)
+	require.NoError(t, err)
+
+	log.Info("before")
+	require.NoError(t, closeLog())
+	require.NoError(t, closeLog())
+
+	log.Info("after") // to stderr
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"msg":"before"`)
+	assert.NotContains(t, string(data), `"msg":"after"`)
+}
+
+func TestNew_Tees(t *testing.T) {
+	t.Parallel()
+
+	tee, logs := observer.New(zapcore.ErrorLevel)
+	cfg := tcfg.Log{Format: tcfg.LogFormatJSON, Output: []string{filepath.Join(t.TempDir(), "app.log")}}
+	cfg.Dedup.Window = 60
+
+	log, closeLog, err := New(cfg, zapcore.InfoLevel, tee)
+	require.NoError(t, err)
+
+	log.Info("not teed")
+
+	for range 5 {
+		log.Error("failed")
+	}
+
+	require.NoError(t, closeLog())
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "failed", entries[0].Message)
+}
@@ -0,0 +1,95 @@
+package tzap
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+const (
+	requestIDLen    = 16
+	requestIDMaxLen = 128
+)
+
+type (
+	ctxLoggerKey    struct{}
+	ctxRequestIDKey struct{}
+)
+
+// WithLogger returns a copy of the context holding the logger.
+func WithLogger(ctx context.Context, log *zap.Logger) context.Context {
+	return context.WithValue(ctx, ctxLoggerKey{}, log)
+}
+
+// WithContext returns a copy of the context holding its logger (see [FromContext]) with the fields added.
+func WithContext(ctx context.Context, fields ...zap.Field) context.Context {
+	return WithLogger(ctx, loggerFromContext(ctx).With(fields...))
+}
+
+// FromContext returns the logger held by the context with [TraceFields] of the current span added.
+// It falls back to the global logger, which is the app one once the app is created.
+func FromContext(ctx context.Context) *zap.Logger {
+	log := loggerFromContext(ctx)
+
+	if fields := TraceFields(ctx); len(fields) > 0 {
+		log = log.With(fields...)
+	}
+
+	return log
+}
+
+// contextFields returns the request ID and [TraceFields] of the context, for the loggers not taken from it.
+func contextFields(ctx context.Context) []zap.Field {
+	fields := TraceFields(ctx)
+
+	if id := RequestID(ctx); id != "" {
+		fields = append(fields, zap.String(KeyRequestID, id))
+	}
+
+	return fields
+}
+
+func loggerFromContext(ctx context.Context) *zap.Logger {
+	if log, ok := ctx.Value(ctxLoggerKey{}).(*zap.Logger); ok {
+		return log
+	}
+
+	return zap.L()
+}
+
+// WithRequestID returns a copy of the context holding the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxRequestIDKey{}, id)
+}
+
+// RequestID returns the request ID held by the context or an empty string.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(ctxRequestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID generates a random request ID.
+func NewRequestID() string {
+	b := make([]byte, requestIDLen)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// requestIDOrNew returns the ID received from a client if it's sane, or a new one.
+func requestIDOrNew(id string) string {
+	if id == "" || len(id) > requestIDMaxLen {
+		return NewRequestID()
+	}
+
+	for _, r := range id {
+		if r > '~' || !strconv.IsPrint(r) || r == ' ' {
+			return NewRequestID()
+		}
+	}
+
+	return id
+}
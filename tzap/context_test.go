@@ -0,0 +1,56 @@
+package tzap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	assert.Same(t, zap.L(), FromContext(context.Background()))
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	ctx := WithLogger(context.Background(), zap.New(core))
+	ctx = WithContext(ctx, zap.String("a", "b"))
+
+	FromContext(ctx).Info("msg")
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, map[string]any{"a": "b"}, logs.All()[0].ContextMap())
+}
+
+func TestHTTPContext(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	handler := HTTPContext(zap.New(core))(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Info("handled", zap.String("rid", RequestID(r.Context())))
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set(HeaderRequestID, "abc")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, map[string]any{"request_id": "abc", "method": "POST", "rid": "abc"}, logs.All()[0].ContextMap())
+	assert.Equal(t, "abc", w.Header().Get(HeaderRequestID))
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(HeaderRequestID, "bad id")
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	require.Equal(t, 2, logs.Len())
+	assert.Len(t, w.Header().Get(HeaderRequestID), requestIDLen*2)
+}
@@ -0,0 +1,37 @@
+package tzap
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+var _ zapcore.Core = (*callerCore)(nil)
+
+// callerCore drops the caller from entries below the threshold level.
+type callerCore struct {
+	zapcore.Core
+	level zapcore.Level
+}
+
+func newCallerCore(core zapcore.Core, level zapcore.Level) *callerCore {
+	return &callerCore{Core: core, level: level}
+}
+
+func (c *callerCore) With(fields []zapcore.Field) zapcore.Core {
+	return newCallerCore(c.Core.With(fields), c.level)
+}
+
+func (c *callerCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+func (c *callerCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level < c.level {
+		ent.Caller = zapcore.EntryCaller{}
+	}
+
+	return c.Core.Write(ent, fields)
+}
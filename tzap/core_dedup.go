@@ -0,0 +1,199 @@
+package tzap
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var _ zapcore.Core = (*dedupCore)(nil)
+
+// NewDedupCore wraps the core to fold identical entries logged within the window.
+// Entries are identical if they have the same level, logger name, message and error (see [Err]).
+// The first one is written as is, the repeated ones are counted and reported with a copy of the first one
+// having the [KeyRepeated] field once the window is over, by a timer if nothing is logged meanwhile, or on Sync.
+// Entries at [zapcore.DPanicLevel] and above are never folded.
+func NewDedupCore(core zapcore.Core, window time.Duration) zapcore.Core {
+	return &dedupCore{
+		Core: core,
+		state: &dedupState{
+			window:  window,
+			entries: make(map[dedupKey]*dedupEntry),
+			now:     time.Now,
+		},
+	}
+}
+
+type dedupCore struct {
+	zapcore.Core
+	state *dedupState
+}
+
+type dedupState struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	entries   map[dedupKey]*dedupEntry
+	lastSweep time.Time
+}
+
+type dedupKey struct {
+	level   zapcore.Level
+	logger  string
+	message string
+	err     string
+}
+
+type dedupEntry struct {
+	core     zapcore.Core
+	ent      zapcore.Entry
+	fields   []zapcore.Field
+	until    time.Time
+	repeated int
+}
+
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dedupCore{Core: c.Core.With(fields), state: c.state}
+}
+
+func (c *dedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+func (c *dedupCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level >= zapcore.DPanicLevel {
+		return c.Core.Write(ent, fields)
+	}
+
+	key := dedupKey{level: ent.Level, logger: ent.LoggerName, message: ent.Message, err: errorText(fields)}
+
+	s := c.state
+	s.mu.Lock()
+
+	now := s.now()
+	pending := s.sweep(now)
+
+	if e, ok := s.entries[key]; ok {
+		if now.Before(e.until) {
+			if e.repeated == 0 {
+				time.AfterFunc(e.until.Sub(now), s.expire)
+			}
+
+			e.repeated++
+			s.mu.Unlock()
+
+			return nil
+		}
+
+		if e.repeated > 0 {
+			pending = append(pending, *e)
+		}
+	}
+
+	s.entries[key] = &dedupEntry{core: c.Core, ent: ent, fields: slices.Clone(fields), until: now.Add(s.window)}
+	s.mu.Unlock()
+
+	return errors.Join(writeRepeated(pending, now), c.Core.Write(ent, fields))
+}
+
+func (c *dedupCore) Sync() error {
+	s := c.state
+	s.mu.Lock()
+
+	now := s.now()
+
+	var pending []dedupEntry
+
+	for _, e := range s.entries {
+		if e.repeated > 0 {
+			pending = append(pending, *e)
+			e.repeated = 0
+		}
+	}
+
+	s.mu.Unlock()
+
+	return errors.Join(writeRepeated(pending, now), c.Core.Sync())
+}
+
+// expire reports the repeated entries with the window over, so the tail of a burst isn't held until Sync.
+func (s *dedupState) expire() {
+	s.mu.Lock()
+	now := s.now()
+	pending := s.expired(now)
+	s.mu.Unlock()
+
+	_ = writeRepeated(pending, now) // nowhere to return the error to
+}
+
+// sweep calls expired at most once per window, must be called with mu held.
+func (s *dedupState) sweep(now time.Time) []dedupEntry {
+	if now.Sub(s.lastSweep) < s.window {
+		return nil
+	}
+
+	s.lastSweep = now
+
+	return s.expired(now)
+}
+
+// expired removes the entries with the window over and returns the repeated ones, must be called with mu held.
+func (s *dedupState) expired(now time.Time) []dedupEntry {
+	var pending []dedupEntry
+
+	for key, e := range s.entries {
+		if now.Before(e.until) {
+			continue
+		}
+
+		if e.repeated > 0 {
+			pending = append(pending, *e)
+		}
+
+		delete(s.entries, key)
+	}
+
+	return pending
+}
+
+// writeRepeated writes the summaries in the order the entries were first seen, not in the one of the map.
+func writeRepeated(entries []dedupEntry, now time.Time) error {
+	slices.SortFunc(entries, func(a, b dedupEntry) int {
+		return cmp.Or(
+			a.ent.Time.Compare(b.ent.Time),
+			strings.Compare(a.ent.LoggerName, b.ent.LoggerName),
+			strings.Compare(a.ent.Message, b.ent.Message),
+		)
+	})
+
+	var errs []error
+
+	for _, e := range entries {
+		e.ent.Time = now
+		errs = append(errs, e.core.Write(e.ent, slices.Concat(e.fields, []zapcore.Field{zap.Int(KeyRepeated, e.repeated)})))
+	}
+
+	return errors.Join(errs...)
+}
+
+// errorText returns the message of the first error field.
+func errorText(fields []zapcore.Field) string {
+	for _, f := range fields {
+		if err, ok := FieldError(f); ok {
+			return err.Error()
+		}
+	}
+
+	return ""
+}
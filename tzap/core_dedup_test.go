@@ -0,0 +1,75 @@
+package tzap
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestDedupCore(t *testing.T) {
+	t.Parallel()
+
+	obs, logs := observer.New(zapcore.DebugLevel)
+	core := NewDedupCore(obs, time.Second)
+
+	now := time.Unix(0, 0)
+	dc, ok := core.(*dedupCore)
+	require.True(t, ok)
+	dc.state.now = func() time.Time { return now }
+
+	log := zap.New(core)
+	errBoom := errors.New("boom")
+
+	for range 5 {
+		log.Error("ping failed", zap.Error(errBoom))
+		log.Named("db").Error("ping failed", Err(errBoom))
+	}
+
+	log.Error("ping failed", zap.Error(errors.New("other")))
+	require.Len(t, logs.All(), 3)
+
+	now = now.Add(time.Second)
+	log.Error("ping failed", zap.Error(errBoom))
+
+	entries := logs.TakeAll()
+	require.Len(t, entries, 6)
+	assert.Equal(t, map[string]any{"error": "boom"}, entries[5].ContextMap())
+
+	summaries := make(map[string]map[string]any)
+	for _, e := range entries[3:5] {
+		summaries[e.LoggerName] = e.ContextMap()
+	}
+
+	require.Len(t, summaries, 2)
+	assert.Equal(t, map[string]any{"error": "boom", KeyRepeated: int64(4)}, summaries[""])
+	assert.Equal(t, int64(4), summaries["db"][KeyRepeated])
+
+	log.Error("ping failed", zap.Error(errBoom))
+	require.NoError(t, log.Sync())
+
+	entries = logs.TakeAll()
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(1), entries[0].ContextMap()[KeyRepeated])
+}
+
+func TestDedupCore_Tail(t *testing.T) {
+	t.Parallel()
+
+	obs, logs := observer.New(zapcore.DebugLevel)
+	log := zap.New(NewDedupCore(obs, 50*time.Millisecond))
+
+	for range 3 {
+		log.Warn("retrying")
+	}
+
+	require.Len(t, logs.All(), 1)
+	require.Eventually(t, func() bool { return logs.Len() == 2 }, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, int64(2), logs.All()[1].ContextMap()[KeyRepeated])
+}
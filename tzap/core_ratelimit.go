@@ -0,0 +1,104 @@
+package tzap
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var _ zapcore.Core = (*rateLimitCore)(nil)
+
+// NewRateLimitCore wraps the core to limit the number of entries per second of each named logger
+// with a token bucket of `burst` tokens (`rate` if not positive) refilled at `rate` tokens per second.
+// The first entry let through after some were dropped has the [KeyRateLimited] field with their number.
+// Entries at [zapcore.DPanicLevel] and above are never dropped.
+func NewRateLimitCore(core zapcore.Core, rate, burst int) zapcore.Core {
+	if burst <= 0 {
+		burst = rate
+	}
+
+	return &rateLimitCore{
+		Core: core,
+		state: &rateLimitState{
+			rate:    float64(rate),
+			burst:   float64(burst),
+			buckets: make(map[string]*rateLimitBucket),
+			now:     time.Now,
+		},
+	}
+}
+
+type rateLimitCore struct {
+	zapcore.Core
+	state *rateLimitState
+}
+
+type rateLimitState struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*rateLimitBucket
+}
+
+type rateLimitBucket struct {
+	tokens  float64
+	last    time.Time
+	dropped uint64
+}
+
+func (c *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rateLimitCore{Core: c.Core.With(fields), state: c.state}
+}
+
+func (c *rateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+
+	if ent.Level >= zapcore.DPanicLevel {
+		return c.Core.Check(ent, ce)
+	}
+
+	allowed, dropped := c.state.allow(ent.LoggerName)
+	if !allowed {
+		return ce
+	}
+
+	if dropped > 0 {
+		return c.Core.With([]zapcore.Field{zap.Uint64(KeyRateLimited, dropped)}).Check(ent, ce)
+	}
+
+	return c.Core.Check(ent, ce)
+}
+
+// allow takes a token from the logger bucket, returning the number of entries dropped since the last allowed one.
+func (s *rateLimitState) allow(logger string) (bool, uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	b, ok := s.buckets[logger]
+	if !ok {
+		b = &rateLimitBucket{tokens: s.burst, last: now}
+		s.buckets[logger] = b
+	}
+
+	b.tokens = min(s.burst, b.tokens+now.Sub(b.last).Seconds()*s.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		b.dropped++
+		return false, 0
+	}
+
+	b.tokens--
+	dropped := b.dropped
+	b.dropped = 0
+
+	return true, dropped
+}
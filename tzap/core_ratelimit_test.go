@@ -0,0 +1,45 @@
+package tzap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRateLimitCore(t *testing.T) {
+	t.Parallel()
+
+	obs, logs := observer.New(zapcore.InfoLevel)
+	core := NewRateLimitCore(obs, 2, 3)
+
+	now := time.Unix(0, 0)
+	rc, ok := core.(*rateLimitCore)
+	require.True(t, ok)
+	rc.state.now = func() time.Time { return now }
+
+	log := zap.New(core)
+
+	for range 10 {
+		log.Debug("disabled")
+		log.Info("a")
+		log.Named("other").Info("b")
+	}
+
+	assert.Len(t, logs.FilterMessage("a").All(), 3)
+	assert.Len(t, logs.FilterMessage("b").All(), 3)
+
+	now = now.Add(time.Second)
+	log.Info("a")
+	log.Info("a")
+	log.Info("a")
+
+	entries := logs.FilterMessage("a").All()
+	require.Len(t, entries, 5)
+	assert.Equal(t, map[string]any{KeyRateLimited: uint64(7)}, entries[3].ContextMap())
+	assert.Empty(t, entries[4].ContextMap())
+}
@@ -0,0 +1,63 @@
+package tzap
+
+import (
+	"errors"
+
+	"go.uber.org/zap/zapcore"
+)
+
+var _ zapcore.Core = (levelTee)(nil)
+
+// levelTee duplicates the entries into the cores like [zapcore.NewTee], but checks the level of each core on Write,
+// since the cores wrapping it add themselves on Check instead of calling the one of the tee.
+type levelTee []zapcore.Core
+
+func (t levelTee) Enabled(lvl zapcore.Level) bool {
+	for _, c := range t {
+		if c.Enabled(lvl) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t levelTee) With(fields []zapcore.Field) zapcore.Core {
+	tee := make(levelTee, len(t))
+
+	for i, c := range t {
+		tee[i] = c.With(fields)
+	}
+
+	return tee
+}
+
+func (t levelTee) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if t.Enabled(ent.Level) {
+		return ce.AddCore(ent, t)
+	}
+
+	return ce
+}
+
+func (t levelTee) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	var errs error
+
+	for _, c := range t {
+		if c.Enabled(ent.Level) {
+			errs = errors.Join(errs, c.Write(ent, fields))
+		}
+	}
+
+	return errs
+}
+
+func (t levelTee) Sync() error {
+	var errs error
+
+	for _, c := range t {
+		errs = errors.Join(errs, c.Sync())
+	}
+
+	return errs
+}
@@ -0,0 +1,506 @@
+package tzap
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	logfmtPool = buffer.NewPool()
+
+	_ zapcore.Encoder               = (*logfmtEncoder)(nil)
+	_ zapcore.PrimitiveArrayEncoder = (*logfmtValueEncoder)(nil)
+)
+
+// NewLogfmtEncoder creates an encoder writing entries as `key=value` pairs separated by spaces.
+//
+// Nested objects are flattened with dot-separated keys (`http_request.method=GET`),
+// arrays of primitives become comma-separated values, anything else is rendered as JSON.
+func NewLogfmtEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return &logfmtEncoder{
+		cfg: &cfg,
+		buf: logfmtPool.Get(),
+	}
+}
+
+type logfmtEncoder struct {
+	cfg       *zapcore.EncoderConfig
+	buf       *buffer.Buffer
+	namespace string // prefix for the keys, set by OpenNamespace
+}
+
+func (e *logfmtEncoder) Clone() zapcore.Encoder {
+	clone := e.clone()
+	_, _ = clone.buf.Write(e.buf.Bytes())
+
+	return clone
+}
+
+func (e *logfmtEncoder) clone() *logfmtEncoder {
+	return &logfmtEncoder{
+		cfg:       e.cfg,
+		buf:       logfmtPool.Get(),
+		namespace: e.namespace,
+	}
+}
+
+func (e *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := e.clone()
+	final.namespace = ""
+
+	if final.cfg.TimeKey != "" {
+		if final.cfg.EncodeTime != nil {
+			final.addPrimitive(final.cfg.TimeKey, func(enc zapcore.PrimitiveArrayEncoder) { final.cfg.EncodeTime(ent.Time, enc) })
+		} else {
+			final.AddInt64(final.cfg.TimeKey, ent.Time.UnixNano())
+		}
+	}
+
+	if final.cfg.LevelKey != "" {
+		if final.cfg.EncodeLevel != nil {
+			final.addPrimitive(final.cfg.LevelKey, func(enc zapcore.PrimitiveArrayEncoder) { final.cfg.EncodeLevel(ent.Level, enc) })
+		} else {
+			final.AddString(final.cfg.LevelKey, ent.Level.String())
+		}
+	}
+
+	if ent.LoggerName != "" && final.cfg.NameKey != "" {
+		if final.cfg.EncodeName != nil {
+			final.addPrimitive(final.cfg.NameKey, func(enc zapcore.PrimitiveArrayEncoder) { final.cfg.EncodeName(ent.LoggerName, enc) })
+		} else {
+			final.AddString(final.cfg.NameKey, ent.LoggerName)
+		}
+	}
+
+	if ent.Caller.Defined {
+		if final.cfg.CallerKey != "" {
+			if final.cfg.EncodeCaller != nil {
+				final.addPrimitive(final.cfg.CallerKey, func(enc zapcore.PrimitiveArrayEncoder) { final.cfg.EncodeCaller(ent.Caller, enc) })
+			} else {
+				final.AddString(final.cfg.CallerKey, ent.Caller.String())
+			}
+		}
+
+		if final.cfg.FunctionKey != "" {
+			final.AddString(final.cfg.FunctionKey, ent.Caller.Function)
+		}
+	}
+
+	if final.cfg.MessageKey != "" {
+		final.AddString(final.cfg.MessageKey, ent.Message)
+	}
+
+	if e.buf.Len() > 0 {
+		final.addSeparator()
+		_, _ = final.buf.Write(e.buf.Bytes())
+	}
+
+	final.namespace = e.namespace
+
+	for i := range fields {
+		fields[i].AddTo(final)
+	}
+
+	final.namespace = ""
+
+	if ent.Stack != "" && final.cfg.StacktraceKey != "" {
+		final.AddString(final.cfg.StacktraceKey, ent.Stack)
+	}
+
+	if !final.cfg.SkipLineEnding {
+		if final.cfg.LineEnding != "" {
+			final.buf.AppendString(final.cfg.LineEnding)
+		} else {
+			final.buf.AppendString(zapcore.DefaultLineEnding)
+		}
+	}
+
+	return final.buf, nil
+}
+
+func (e *logfmtEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	enc := &logfmtValueEncoder{cfg: e.cfg}
+	err := marshaler.MarshalLogArray(enc)
+	e.addValue(key, strings.Join(enc.values, ","))
+
+	return err
+}
+
+func (e *logfmtEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	ns := e.namespace
+	e.namespace = e.key(key) + "."
+
+	defer func() { e.namespace = ns }()
+
+	return marshaler.MarshalLogObject(e)
+}
+
+func (e *logfmtEncoder) AddBinary(key string, value []byte) {
+	e.AddString(key, base64.StdEncoding.EncodeToString(value))
+}
+
+func (e *logfmtEncoder) AddByteString(key string, value []byte) {
+	e.AddString(key, string(value))
+}
+
+func (e *logfmtEncoder) AddBool(key string, value bool) {
+	e.addRaw(key, strconv.FormatBool(value))
+}
+
+func (e *logfmtEncoder) AddComplex128(key string, value complex128) {
+	e.addRaw(key, formatComplex(value, 128))
+}
+
+func (e *logfmtEncoder) AddComplex64(key string, value complex64) {
+	e.addRaw(key, formatComplex(complex128(value), 64))
+}
+
+func (e *logfmtEncoder) AddDuration(key string, value time.Duration) {
+	if e.cfg.EncodeDuration != nil {
+		e.addPrimitive(key, func(enc zapcore.PrimitiveArrayEncoder) { e.cfg.EncodeDuration(value, enc) })
+	} else {
+		e.AddInt64(key, int64(value))
+	}
+}
+
+func (e *logfmtEncoder) AddFloat64(key string, value float64) {
+	e.addRaw(key, formatFloat(value, 64))
+}
+
+func (e *logfmtEncoder) AddFloat32(key string, value float32) {
+	e.addRaw(key, formatFloat(float64(value), 32))
+}
+
+func (e *logfmtEncoder) AddInt(key string, value int) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *logfmtEncoder) AddInt64(key string, value int64) {
+	e.addRaw(key, strconv.FormatInt(value, 10))
+}
+
+func (e *logfmtEncoder) AddInt32(key string, value int32) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *logfmtEncoder) AddInt16(key string, value int16) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *logfmtEncoder) AddInt8(key string, value int8) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *logfmtEncoder) AddString(key, value string) {
+	e.addValue(key, value)
+}
+
+func (e *logfmtEncoder) AddUint(key string, value uint) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *logfmtEncoder) AddUint64(key string, value uint64) {
+	e.addRaw(key, strconv.FormatUint(value, 10))
+}
+
+func (e *logfmtEncoder) AddUint32(key string, value uint32) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *logfmtEncoder) AddUint16(key string, value uint16) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *logfmtEncoder) AddUint8(key string, value uint8) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *logfmtEncoder) AddUintptr(key string, value uintptr) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *logfmtEncoder) AddTime(key string, value time.Time) {
+	if e.cfg.EncodeTime != nil {
+		e.addPrimitive(key, func(enc zapcore.PrimitiveArrayEncoder) { e.cfg.EncodeTime(value, enc) })
+	} else {
+		e.AddInt64(key, value.UnixNano())
+	}
+}
+
+func (e *logfmtEncoder) AddReflected(key string, value any) error {
+	b, err := marshalReflected(e.cfg, value)
+	if err != nil {
+		return err
+	}
+
+	e.addValue(key, string(b))
+
+	return nil
+}
+
+func (e *logfmtEncoder) OpenNamespace(key string) {
+	e.namespace = e.key(key) + "."
+}
+
+func (e *logfmtEncoder) key(key string) string {
+	return e.namespace + key
+}
+
+func (e *logfmtEncoder) addPrimitive(key string, fn func(enc zapcore.PrimitiveArrayEncoder)) {
+	enc := &logfmtValueEncoder{cfg: e.cfg}
+	fn(enc)
+	e.addValue(key, strings.Join(enc.values, " "))
+}
+
+func (e *logfmtEncoder) addSeparator() {
+	if e.buf.Len() > 0 {
+		e.buf.AppendByte(' ')
+	}
+}
+
+func (e *logfmtEncoder) addKey(key string) {
+	e.addSeparator()
+	appendLogfmtKey(e.buf, e.key(key))
+	e.buf.AppendByte('=')
+}
+
+// addRaw adds a value that never needs quoting.
+func (e *logfmtEncoder) addRaw(key, value string) {
+	e.addKey(key)
+	e.buf.AppendString(value)
+}
+
+func (e *logfmtEncoder) addValue(key, value string) {
+	e.addKey(key)
+	appendLogfmtValue(e.buf, value)
+}
+
+// logfmtValueEncoder collects array elements and the output of entry-level encoders as strings.
+type logfmtValueEncoder struct {
+	cfg    *zapcore.EncoderConfig
+	values []string
+}
+
+func (e *logfmtValueEncoder) AppendArray(marshaler zapcore.ArrayMarshaler) error {
+	return e.appendJSON(func(enc zapcore.ObjectEncoder) error { return enc.AddArray("v", marshaler) })
+}
+
+func (e *logfmtValueEncoder) AppendObject(marshaler zapcore.ObjectMarshaler) error {
+	return e.appendJSON(func(enc zapcore.ObjectEncoder) error { return enc.AddObject("v", marshaler) })
+}
+
+func (e *logfmtValueEncoder) AppendReflected(value any) error {
+	b, err := marshalReflected(e.cfg, value)
+	if err != nil {
+		return err
+	}
+
+	e.values = append(e.values, string(b))
+
+	return nil
+}
+
+func (e *logfmtValueEncoder) AppendBool(v bool) {
+	e.append(strconv.FormatBool(v))
+}
+
+func (e *logfmtValueEncoder) AppendByteString(v []byte) {
+	e.append(string(v))
+}
+
+func (e *logfmtValueEncoder) AppendComplex128(v complex128) {
+	e.append(formatComplex(v, 128))
+}
+
+func (e *logfmtValueEncoder) AppendComplex64(v complex64) {
+	e.append(formatComplex(complex128(v), 64))
+}
+
+func (e *logfmtValueEncoder) AppendFloat64(v float64) {
+	e.append(formatFloat(v, 64))
+}
+
+func (e *logfmtValueEncoder) AppendFloat32(v float32) {
+	e.append(formatFloat(float64(v), 32))
+}
+
+func (e *logfmtValueEncoder) AppendInt(v int) {
+	e.AppendInt64(int64(v))
+}
+
+func (e *logfmtValueEncoder) AppendInt64(v int64) {
+	e.append(strconv.FormatInt(v, 10))
+}
+
+func (e *logfmtValueEncoder) AppendInt32(v int32) {
+	e.AppendInt64(int64(v))
+}
+
+func (e *logfmtValueEncoder) AppendInt16(v int16) {
+	e.AppendInt64(int64(v))
+}
+
+func (e *logfmtValueEncoder) AppendInt8(v int8) {
+	e.AppendInt64(int64(v))
+}
+
+func (e *logfmtValueEncoder) AppendString(v string) {
+	e.append(v)
+}
+
+func (e *logfmtValueEncoder) AppendUint(v uint) {
+	e.AppendUint64(uint64(v))
+}
+
+func (e *logfmtValueEncoder) AppendUint64(v uint64) {
+	e.append(strconv.FormatUint(v, 10))
+}
+
+func (e *logfmtValueEncoder) AppendUint32(v uint32) {
+	e.AppendUint64(uint64(v))
+}
+
+func (e *logfmtValueEncoder) AppendUint16(v uint16) {
+	e.AppendUint64(uint64(v))
+}
+
+func (e *logfmtValueEncoder) AppendUint8(v uint8) {
+	e.AppendUint64(uint64(v))
+}
+
+func (e *logfmtValueEncoder) AppendUintptr(v uintptr) {
+	e.AppendUint64(uint64(v))
+}
+
+func (e *logfmtValueEncoder) AppendDuration(v time.Duration) {
+	e.appendDuration(v)
+}
+
+func (e *logfmtValueEncoder) AppendTime(v time.Time) {
+	e.appendTime(v)
+}
+
+func (e *logfmtValueEncoder) append(v string) {
+	e.values = append(e.values, v)
+}
+
+func (e *logfmtValueEncoder) appendDuration(v time.Duration) {
+	if e.cfg.EncodeDuration != nil {
+		e.cfg.EncodeDuration(v, e)
+	} else {
+		e.AppendInt64(int64(v))
+	}
+}
+
+func (e *logfmtValueEncoder) appendTime(v time.Time) {
+	if e.cfg.EncodeTime != nil {
+		e.cfg.EncodeTime(v, e)
+	} else {
+		e.AppendInt64(v.UnixNano())
+	}
+}
+
+// appendJSON renders a nested value with the JSON encoder, so arrays of objects stay readable.
+func (e *logfmtValueEncoder) appendJSON(add func(enc zapcore.ObjectEncoder) error) error {
+	cfg := zapcore.EncoderConfig{
+		EncodeTime:          e.cfg.EncodeTime,
+		EncodeDuration:      e.cfg.EncodeDuration,
+		NewReflectedEncoder: e.cfg.NewReflectedEncoder,
+		SkipLineEnding:      true,
+	}
+
+	enc := zapcore.NewJSONEncoder(cfg)
+	if err := add(enc); err != nil {
+		return err
+	}
+
+	buf, err := enc.EncodeEntry(zapcore.Entry{}, nil)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+
+	// strip the wrapping `{"v":` and `}`
+	s := buf.String()
+	e.append(s[len(`{"v":`) : len(s)-1])
+
+	return nil
+}
+
+func marshalReflected(cfg *zapcore.EncoderConfig, value any) ([]byte, error) {
+	if cfg.NewReflectedEncoder == nil {
+		return json.Marshal(value)
+	}
+
+	buf := logfmtPool.Get()
+	defer buf.Free()
+
+	if err := cfg.NewReflectedEncoder(buf).Encode(value); err != nil {
+		return nil, err
+	}
+
+	return []byte(strings.TrimRight(buf.String(), "\n")), nil
+}
+
+func formatFloat(v float64, bitSize int) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'f', -1, bitSize)
+	}
+}
+
+func formatComplex(v complex128, bitSize int) string {
+	return strconv.FormatComplex(v, 'f', -1, bitSize)
+}
+
+func appendLogfmtKey(buf *buffer.Buffer, key string) {
+	if key == "" {
+		buf.AppendByte('_')
+		return
+	}
+
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError {
+			buf.AppendByte('_')
+		} else {
+			buf.AppendString(string(r))
+		}
+	}
+}
+
+func appendLogfmtValue(buf *buffer.Buffer, value string) {
+	if !logfmtNeedsQuote(value) {
+		buf.AppendString(value)
+		return
+	}
+
+	buf.AppendString(strconv.Quote(value))
+}
+
+func logfmtNeedsQuote(value string) bool {
+	if value == "" {
+		return true
+	}
+
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !strconv.IsPrint(r) {
+			return true
+		}
+	}
+
+	return false
+}
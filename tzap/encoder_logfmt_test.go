@@ -0,0 +1,51 @@
+package tzap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogfmtEncoder_EncodeEntry(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/path?q=1", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+
+	enc := NewLogfmtEncoder(DefaultStdCoreConfig(nil).EncoderConfig)
+	enc = enc.Clone()
+	zap.String("env", "dev").AddTo(enc)
+
+	ent := zapcore.Entry{
+		Level:      zapcore.WarnLevel,
+		Time:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		LoggerName: "app.http",
+		Message:    "request failed",
+	}
+
+	buf, err := enc.EncodeEntry(ent, []zapcore.Field{
+		zap.Int("attempt", 2),
+		zap.Strings("tags", []string{"a", "b"}),
+		zap.String("empty", ""),
+		zap.String("quoted", `say "hi"`),
+		zap.Namespace("ns"),
+		zap.Bool("ok", false),
+		HTTPRequest(r),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		`ts=2024-01-02T03:04:05Z level=WARN logger=app.http msg="request failed" env=dev `+
+			`attempt=2 tags=a,b empty="" quoted="say \"hi\"" ns.ok=false `+
+			`ns.http_request.method=GET ns.http_request.url="/path?q=1" ns.http_request.proto=HTTP/1.1 `+
+			`ns.http_request.contentLength=0 ns.http_request.headers.Authorization=..*13*..`+"\n",
+		buf.String(),
+	)
+}
@@ -0,0 +1,372 @@
+package tzap
+
+import (
+	"encoding/base64"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/heffcodex/the/tcfg"
+)
+
+const (
+	prettyTimeLayout    = "15:04:05.000"
+	prettyNameWidthMin  = 12
+	prettyIndent        = "    "
+	prettyNestedIndent  = "  "
+	prettyColorReset    = "\x1b[0m"
+	prettyColorDim      = "\x1b[2m"
+	prettyColorBold     = "\x1b[1m"
+	prettyColorRed      = "\x1b[31m"
+	prettyColorBoldRed  = "\x1b[1;31m"
+	prettyColorYellow   = "\x1b[33m"
+	prettyColorBlue     = "\x1b[34m"
+	prettyColorMagenta  = "\x1b[35m"
+	prettyColorCyan     = "\x1b[36m"
+	prettyLevelMaxWidth = 6 // len("DPANIC")
+)
+
+var (
+	prettyPool = buffer.NewPool()
+
+	_ zapcore.Encoder = (*prettyEncoder)(nil)
+)
+
+// NewPrettyEncoder creates a human-friendly encoder meant for development on a terminal.
+//
+// Each entry starts with a line holding the time, the level, the logger name padded to the longest one seen so far,
+// the message and the primitive fields as `key=value`.
+// Object fields (like [HTTPRequest]) and the stacktrace follow on separate indented lines.
+// Keys set to [zapcore.OmitKey] in the config are omitted, the encoder funcs are ignored.
+func NewPrettyEncoder(cfg zapcore.EncoderConfig, color bool) zapcore.Encoder {
+	width := new(atomic.Int64)
+	width.Store(prettyNameWidthMin)
+
+	return &prettyEncoder{
+		cfg:       &cfg,
+		color:     color,
+		nameWidth: width,
+		inline:    prettyPool.Get(),
+		block:     prettyPool.Get(),
+	}
+}
+
+// IsTerminal reports whether all the outputs are stdout or stderr attached to a terminal.
+func IsTerminal(outputs ...string) bool {
+	if len(outputs) == 0 {
+		return false
+	}
+
+	for _, output := range outputs {
+		var f *os.File
+
+		switch output {
+		case tcfg.LogOutputStdout:
+			f = os.Stdout
+		case tcfg.LogOutputStderr:
+			f = os.Stderr
+		default:
+			return false
+		}
+
+		if !isatty.IsTerminal(f.Fd()) && !isatty.IsCygwinTerminal(f.Fd()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type prettyEncoder struct {
+	cfg       *zapcore.EncoderConfig
+	color     bool
+	nameWidth *atomic.Int64 // shared between clones to keep the names aligned
+
+	inline    *buffer.Buffer // ` key=value` pairs of the first line
+	block     *buffer.Buffer // indented lines of the object fields
+	depth     int            // object nesting level, zero for the top-level fields
+	namespace string
+}
+
+func (e *prettyEncoder) Clone() zapcore.Encoder {
+	clone := e.clone()
+	_, _ = clone.inline.Write(e.inline.Bytes())
+	_, _ = clone.block.Write(e.block.Bytes())
+
+	return clone
+}
+
+func (e *prettyEncoder) clone() *prettyEncoder {
+	return &prettyEncoder{
+		cfg:       e.cfg,
+		color:     e.color,
+		nameWidth: e.nameWidth,
+		inline:    prettyPool.Get(),
+		block:     prettyPool.Get(),
+		namespace: e.namespace,
+	}
+}
+
+func (e *prettyEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	fieldEnc := e.clone()
+	_, _ = fieldEnc.inline.Write(e.inline.Bytes())
+	_, _ = fieldEnc.block.Write(e.block.Bytes())
+
+	defer func() {
+		fieldEnc.inline.Free()
+		fieldEnc.block.Free()
+	}()
+
+	for i := range fields {
+		fields[i].AddTo(fieldEnc)
+	}
+
+	buf := prettyPool.Get()
+
+	if e.cfg.TimeKey != "" {
+		e.colored(buf, prettyColorDim, ent.Time.Format(prettyTimeLayout))
+		buf.AppendByte(' ')
+	}
+
+	if e.cfg.LevelKey != "" {
+		lvl := ent.Level.CapitalString()
+		e.colored(buf, prettyLevelColor(ent.Level), lvl)
+		buf.AppendString(strings.Repeat(" ", max(prettyLevelMaxWidth-len(lvl), 0)+1))
+	}
+
+	if e.cfg.NameKey != "" {
+		width := int(e.nameWidth.Load())
+		if n := len(ent.LoggerName); n > width {
+			e.nameWidth.CompareAndSwap(int64(width), int64(n))
+			width = n
+		}
+
+		e.colored(buf, prettyColorCyan, ent.LoggerName)
+		buf.AppendString(strings.Repeat(" ", width-len(ent.LoggerName)+1))
+	}
+
+	if ent.Caller.Defined && e.cfg.CallerKey != "" {
+		e.colored(buf, prettyColorDim, ent.Caller.TrimmedPath())
+		buf.AppendByte(' ')
+	}
+
+	if e.cfg.MessageKey != "" {
+		e.colored(buf, prettyColorBold, ent.Message)
+	}
+
+	_, _ = buf.Write(fieldEnc.inline.Bytes())
+	buf.AppendByte('\n')
+	_, _ = buf.Write(fieldEnc.block.Bytes())
+
+	if ent.Stack != "" && e.cfg.StacktraceKey != "" {
+		e.appendStack(buf, ent.Stack)
+	}
+
+	if e.cfg.SkipLineEnding {
+		buf.TrimNewline()
+	}
+
+	return buf, nil
+}
+
+func (e *prettyEncoder) appendStack(buf *buffer.Buffer, stack string) {
+	buf.AppendString(prettyIndent)
+	e.colored(buf, prettyColorRed, e.cfg.StacktraceKey)
+	buf.AppendString(":\n")
+
+	for line := range strings.Lines(stack) {
+		line = strings.TrimRight(line, "\n")
+		buf.AppendString(prettyIndent + prettyNestedIndent)
+
+		if file, ok := strings.CutPrefix(line, "\t"); ok {
+			buf.AppendString(prettyNestedIndent + prettyNestedIndent)
+			e.colored(buf, prettyColorDim, file)
+		} else {
+			buf.AppendString(line)
+		}
+
+		buf.AppendByte('\n')
+	}
+}
+
+func (e *prettyEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	enc := &logfmtValueEncoder{cfg: e.cfg}
+	err := marshaler.MarshalLogArray(enc)
+	e.addValue(key, "["+strings.Join(enc.values, ", ")+"]", false)
+
+	return err
+}
+
+func (e *prettyEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	e.block.AppendString(e.indent())
+	e.colored(e.block, prettyColorCyan, e.namespace+key)
+	e.block.AppendString(":\n")
+
+	ns := e.namespace
+	e.namespace = ""
+	e.depth++
+
+	defer func() {
+		e.depth--
+		e.namespace = ns
+	}()
+
+	return marshaler.MarshalLogObject(e)
+}
+
+func (e *prettyEncoder) AddBinary(key string, value []byte) {
+	e.AddString(key, base64.StdEncoding.EncodeToString(value))
+}
+
+func (e *prettyEncoder) AddByteString(key string, value []byte) {
+	e.AddString(key, string(value))
+}
+
+func (e *prettyEncoder) AddBool(key string, value bool) {
+	e.addValue(key, strconv.FormatBool(value), false)
+}
+
+func (e *prettyEncoder) AddComplex128(key string, value complex128) {
+	e.addValue(key, formatComplex(value, 128), false)
+}
+
+func (e *prettyEncoder) AddComplex64(key string, value complex64) {
+	e.addValue(key, formatComplex(complex128(value), 64), false)
+}
+
+func (e *prettyEncoder) AddDuration(key string, value time.Duration) {
+	e.addValue(key, value.String(), false)
+}
+
+func (e *prettyEncoder) AddFloat64(key string, value float64) {
+	e.addValue(key, formatFloat(value, 64), false)
+}
+
+func (e *prettyEncoder) AddFloat32(key string, value float32) {
+	e.addValue(key, formatFloat(float64(value), 32), false)
+}
+
+func (e *prettyEncoder) AddInt(key string, value int) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *prettyEncoder) AddInt64(key string, value int64) {
+	e.addValue(key, strconv.FormatInt(value, 10), false)
+}
+
+func (e *prettyEncoder) AddInt32(key string, value int32) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *prettyEncoder) AddInt16(key string, value int16) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *prettyEncoder) AddInt8(key string, value int8) {
+	e.AddInt64(key, int64(value))
+}
+
+func (e *prettyEncoder) AddString(key, value string) {
+	e.addValue(key, value, true)
+}
+
+func (e *prettyEncoder) AddTime(key string, value time.Time) {
+	e.addValue(key, value.Format(time.RFC3339Nano), false)
+}
+
+func (e *prettyEncoder) AddUint(key string, value uint) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *prettyEncoder) AddUint64(key string, value uint64) {
+	e.addValue(key, strconv.FormatUint(value, 10), false)
+}
+
+func (e *prettyEncoder) AddUint32(key string, value uint32) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *prettyEncoder) AddUint16(key string, value uint16) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *prettyEncoder) AddUint8(key string, value uint8) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *prettyEncoder) AddUintptr(key string, value uintptr) {
+	e.AddUint64(key, uint64(value))
+}
+
+func (e *prettyEncoder) AddReflected(key string, value any) error {
+	b, err := marshalReflected(e.cfg, value)
+	if err != nil {
+		return err
+	}
+
+	e.addValue(key, string(b), false)
+
+	return nil
+}
+
+func (e *prettyEncoder) OpenNamespace(key string) {
+	e.namespace += key + "."
+}
+
+func (e *prettyEncoder) indent() string {
+	return prettyIndent + strings.Repeat(prettyNestedIndent, e.depth)
+}
+
+// addValue writes ` key=value` to the first line for top-level fields and `key: value` lines inside objects.
+func (e *prettyEncoder) addValue(key, value string, quote bool) {
+	if e.depth == 0 {
+		e.inline.AppendByte(' ')
+		e.colored(e.inline, prettyColorDim, e.namespace+key+"=")
+
+		if quote && logfmtNeedsQuote(value) {
+			value = strconv.Quote(value)
+		}
+
+		e.inline.AppendString(value)
+
+		return
+	}
+
+	e.block.AppendString(e.indent())
+	e.colored(e.block, prettyColorDim, e.namespace+key+":")
+	e.block.AppendByte(' ')
+	e.block.AppendString(strings.ReplaceAll(value, "\n", "\n"+e.indent()+prettyNestedIndent))
+	e.block.AppendByte('\n')
+}
+
+func (e *prettyEncoder) colored(buf *buffer.Buffer, color, s string) {
+	if !e.color {
+		buf.AppendString(s)
+		return
+	}
+
+	buf.AppendString(color)
+	buf.AppendString(s)
+	buf.AppendString(prettyColorReset)
+}
+
+func prettyLevelColor(lvl zapcore.Level) string {
+	switch lvl {
+	case zapcore.DebugLevel:
+		return prettyColorMagenta
+	case zapcore.InfoLevel:
+		return prettyColorBlue
+	case zapcore.WarnLevel:
+		return prettyColorYellow
+	case zapcore.ErrorLevel:
+		return prettyColorRed
+	default:
+		return prettyColorBoldRed
+	}
+}
@@ -0,0 +1,53 @@
+package tzap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestPrettyEncoder_EncodeEntry(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/path", nil)
+	r.Header.Set("Accept", "*/*")
+
+	enc := NewPrettyEncoder(DefaultStdCoreConfig(nil).EncoderConfig, false)
+
+	ent := zapcore.Entry{
+		Level:      zapcore.InfoLevel,
+		Time:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		LoggerName: "app.http",
+		Message:    "request",
+		Stack:      "main.main\n\t/src/main.go:10",
+	}
+
+	buf, err := enc.EncodeEntry(ent, []zapcore.Field{
+		zap.Int("attempt", 2),
+		zap.String("note", "two words"),
+		HTTPRequest(r),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		"03:04:05.000 INFO   app.http     request attempt=2 note=\"two words\"\n"+
+			"    http_request:\n"+
+			"      method: GET\n"+
+			"      url: /path\n"+
+			"      proto: HTTP/1.1\n"+
+			"      contentLength: 0\n"+
+			"      headers:\n"+
+			"        Accept: [*/*]\n"+
+			"    stacktrace:\n"+
+			"      main.main\n"+
+			"          /src/main.go:10\n",
+		buf.String(),
+	)
+}
@@ -0,0 +1,174 @@
+package tzap
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	errorTreeMaxDepth = 32
+)
+
+var (
+	_ zapcore.ObjectMarshaler = (*errorMarshaler)(nil)
+	_ zapcore.ArrayMarshaler  = errorCausesMarshaler{}
+)
+
+// Err is [zap.Error] rendering the error as a tree, see [NamedErr].
+func Err(err error) zap.Field {
+	return NamedErr(KeyError, err)
+}
+
+// NamedErr renders the error as an object with the message, and optionally:
+//   - `stack` of the deepest error with a `StackTrace` method returning program counters (e.g. github.com/pkg/errors);
+//   - `context` filled by the errors of the chain implementing [zapcore.ObjectMarshaler];
+//   - `causes` holding the same objects for each of the joined errors.
+//
+// Chains of wrapped errors are collapsed into a single node, since their messages already include the wrapped ones.
+func NamedErr(key string, err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+
+	return zap.Field{Key: key, Type: zapcore.ObjectMarshalerType, Interface: &errorMarshaler{err: err}}
+}
+
+// FieldError returns the error of a [zap.Error], [zap.NamedError], [Err] or [NamedErr] field.
+func FieldError(f zap.Field) (error, bool) {
+	switch v := f.Interface.(type) {
+	case *errorMarshaler:
+		return v.err, true
+	case error:
+		return v, f.Type == zapcore.ErrorType
+	default:
+		return nil, false
+	}
+}
+
+type errorMarshaler struct {
+	err   error
+	depth int
+}
+
+func (m *errorMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("msg", m.err.Error())
+
+	var (
+		stack   []uintptr
+		context []zapcore.ObjectMarshaler
+		causes  []error
+	)
+
+	for err := m.err; err != nil; {
+		if pcs := errorStack(err); len(pcs) > 0 {
+			stack = pcs
+		}
+
+		if om, ok := err.(zapcore.ObjectMarshaler); ok { //nolint:errorlint // every error of the chain is visited
+			context = append(context, om)
+		}
+
+		switch u := err.(type) { //nolint:errorlint // unwrapping manually
+		case interface{ Unwrap() []error }:
+			causes = u.Unwrap()
+			err = nil
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			err = nil
+		}
+	}
+
+	if len(stack) > 0 {
+		enc.AddString("stack", formatStack(stack))
+	}
+
+	if len(context) > 0 {
+		_ = enc.AddObject("context", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			var errs error
+
+			for _, om := range context {
+				errs = errors.Join(errs, om.MarshalLogObject(enc))
+			}
+
+			return errs
+		}))
+	}
+
+	if len(causes) > 0 && m.depth < errorTreeMaxDepth {
+		_ = enc.AddArray("causes", errorCausesMarshaler{causes, m.depth + 1})
+	}
+
+	return nil
+}
+
+type errorCausesMarshaler struct {
+	errs  []error
+	depth int
+}
+
+func (m errorCausesMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, err := range m.errs {
+		if err != nil {
+			_ = enc.AppendObject(&errorMarshaler{err: err, depth: m.depth})
+		}
+	}
+
+	return nil
+}
+
+// errorStack returns the program counters of the error stack if it has a `StackTrace` method returning them
+// as a slice of any uintptr-based type.
+func errorStack(err error) []uintptr {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return nil
+	}
+
+	out := method.Type().Out(0)
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	frames := method.Call(nil)[0]
+	pcs := make([]uintptr, frames.Len())
+
+	for i := range pcs {
+		pcs[i] = uintptr(frames.Index(i).Uint())
+	}
+
+	return pcs
+}
+
+// formatStack renders the stack the way zap does.
+func formatStack(pcs []uintptr) string {
+	var sb strings.Builder
+
+	frames := runtime.CallersFrames(pcs)
+
+	for {
+		frame, more := frames.Next()
+
+		if sb.Len() > 0 {
+			sb.WriteByte('\n')
+		}
+
+		sb.WriteString(frame.Function)
+		sb.WriteString("\n\t")
+		sb.WriteString(frame.File)
+		sb.WriteByte(':')
+		sb.WriteString(strconv.Itoa(frame.Line))
+
+		if !more {
+			break
+		}
+	}
+
+	return sb.String()
+}
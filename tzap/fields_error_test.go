@@ -0,0 +1,73 @@
+package tzap
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type testFrame uintptr
+
+type testStackError struct {
+	msg   string
+	stack []testFrame
+}
+
+func newTestStackError(msg string) *testStackError {
+	pcs := make([]uintptr, 1)
+	runtime.Callers(1, pcs)
+
+	return &testStackError{msg: msg, stack: []testFrame{testFrame(pcs[0])}}
+}
+
+func (e *testStackError) Error() string {
+	return e.msg
+}
+
+func (e *testStackError) StackTrace() []testFrame {
+	return e.stack
+}
+
+func (e *testStackError) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("kind", "stacked")
+	return nil
+}
+
+func TestErr(t *testing.T) {
+	t.Parallel()
+
+	err := errors.Join(
+		fmt.Errorf("db: %w", newTestStackError("boom")),
+		errors.New("cache: closed"),
+	)
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	zap.New(core).Error("close", Err(fmt.Errorf("app: %w", err)), Err(nil))
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 1)
+
+	tree, ok := entries[0].ContextMap()[KeyError].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "app: db: boom\ncache: closed", tree["msg"])
+
+	causes, ok := tree["causes"].([]any)
+	require.True(t, ok)
+	require.Len(t, causes, 2)
+
+	db, ok := causes[0].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "db: boom", db["msg"])
+	assert.Equal(t, map[string]any{"kind": "stacked"}, db["context"])
+	assert.Contains(t, db["stack"], "tzap.newTestStackError")
+	assert.NotContains(t, db, "causes")
+
+	assert.Equal(t, map[string]any{"msg": "cache: closed"}, causes[1])
+}
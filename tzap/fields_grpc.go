@@ -0,0 +1,96 @@
+package tzap
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	_ zapcore.ObjectMarshaler = (*grpcCallMarshaler)(nil)
+)
+
+type grpcCallMarshaler struct {
+	Service  string
+	Method   string
+	Peer     string
+	Deadline time.Time
+	Code     codes.Code
+	Duration time.Duration
+	Metadata httpHeadersMarshaler
+}
+
+func (m *grpcCallMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("service", m.Service)
+	enc.AddString("method", m.Method)
+
+	if m.Peer != "" {
+		enc.AddString("peer", m.Peer)
+	}
+
+	if !m.Deadline.IsZero() {
+		enc.AddTime("deadline", m.Deadline)
+	}
+
+	enc.AddString("code", m.Code.String())
+	enc.AddDuration("duration", m.Duration)
+	_ = enc.AddObject("metadata", m.Metadata)
+
+	return nil
+}
+
+// GRPCServerCall describes a finished server call with the incoming metadata masked as HTTP headers are,
+// see [HTTPRequestMaskHeaders].
+func GRPCServerCall(ctx context.Context, fullMethod string, err error, duration time.Duration) zap.Field {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return grpcCall(ctx, fullMethod, nil, md, err, duration)
+}
+
+// GRPCClientCall is the same as [GRPCServerCall] for client calls with the outgoing metadata.
+// The peer is known only if the call was made with [grpc.Peer] option, pass it as `p`.
+func GRPCClientCall(ctx context.Context, fullMethod string, p *peer.Peer, err error, duration time.Duration) zap.Field {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return grpcCall(ctx, fullMethod, p, md, err, duration)
+}
+
+func grpcCall(ctx context.Context, fullMethod string, p *peer.Peer, md metadata.MD, err error, duration time.Duration) zap.Field {
+	service, method := splitGRPCMethod(fullMethod)
+
+	m := &grpcCallMarshaler{
+		Service:  service,
+		Method:   method,
+		Code:     status.Code(err),
+		Duration: duration,
+		Metadata: httpHeaders(http.Header(md)),
+	}
+
+	if p == nil {
+		p, _ = peer.FromContext(ctx)
+	}
+
+	if p != nil && p.Addr != nil {
+		m.Peer = p.Addr.String()
+	}
+
+	m.Deadline, _ = ctx.Deadline()
+
+	return zap.Field{Key: KeyGRPCCall, Type: zapcore.ObjectMarshalerType, Interface: m}
+}
+
+// splitGRPCMethod splits `/package.Service/Method` into the service and the method.
+func splitGRPCMethod(fullMethod string) (string, string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return "", fullMethod
+	}
+
+	return service, method
+}
@@ -0,0 +1,95 @@
+package tzap
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCUnaryServerLog(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	ctx := metadata.NewIncomingContext(t.Context(), metadata.Pairs("authorization", "Bearer x", "x-tenant", "a"))
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000}})
+	ctx = WithLogger(ctx, zap.New(core))
+
+	interceptor := GRPCUnaryServerLog(nil, GRPCLogOptions{})
+	info := &grpc.UnaryServerInfo{FullMethod: "/pkg.Service/Get"}
+
+	_, err := interceptor(ctx, nil, info, func(context.Context, any) (any, error) {
+		return nil, status.Error(codes.NotFound, "no such thing")
+	})
+	require.Error(t, err)
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+	assert.Equal(t, GRPCLogMessageDefault, entries[0].Message)
+
+	call, ok := entries[0].ContextMap()[KeyGRPCCall].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "pkg.Service", call["service"])
+	assert.Equal(t, "Get", call["method"])
+	assert.Equal(t, "10.0.0.1:5000", call["peer"])
+	assert.Equal(t, codes.NotFound.String(), call["code"])
+	assert.Equal(t, map[string]any{
+		"authorization": []any{"..*8*.."},
+		"x-tenant":      []any{"a"},
+	}, call["metadata"])
+}
+
+func TestGRPCUnaryServerLog_ContextFields(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	interceptor := GRPCUnaryServerLog(zap.New(core), GRPCLogOptions{})
+	info := &grpc.UnaryServerInfo{FullMethod: "/pkg.Service/Get"}
+
+	_, err := interceptor(testSpanContext(t), nil, info, func(context.Context, any) (any, error) {
+		return "ok", nil
+	})
+	require.NoError(t, err)
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", entries[0].ContextMap()[KeyTraceID])
+	assert.Equal(t, "0102030405060708", entries[0].ContextMap()[KeySpanID])
+	assert.NotContains(t, entries[0].ContextMap(), KeyRequestID)
+}
+
+func TestGRPCClientCall(t *testing.T) {
+	t.Parallel()
+
+	deadline := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	ctx, cancel := context.WithDeadline(t.Context(), deadline)
+	defer cancel()
+
+	ctx = metadata.AppendToOutgoingContext(ctx, MetadataRequestID, "abc")
+
+	field := GRPCClientCall(ctx, "bad", nil, nil, time.Second)
+
+	m, ok := field.Interface.(*grpcCallMarshaler)
+	require.True(t, ok)
+	assert.Empty(t, m.Service)
+	assert.Equal(t, "bad", m.Method)
+	assert.Empty(t, m.Peer)
+	assert.Equal(t, deadline, m.Deadline)
+	assert.Equal(t, codes.OK, m.Code)
+	assert.Equal(t, httpHeadersMarshaler{MetadataRequestID: {"abc"}}, m.Metadata)
+}
@@ -0,0 +1,292 @@
+package tzap
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	HTTPBodyMaxSizeDefault = 4 << 10
+)
+
+var (
+	httpBodyContentTypesDefault = []string{
+		"application/json",
+		"application/x-www-form-urlencoded",
+		"text/plain",
+	}
+	httpBodyRedactDefault = []string{
+		"access_token",
+		"card_number",
+		"cardNumber",
+		"cvc",
+		"cvv",
+		"pan",
+		"passwd",
+		"password",
+		"refresh_token",
+		"secret",
+		"token",
+	}
+)
+
+// HTTPBodyConfig enables request body capture, see [HTTPRequestWithBody].
+type HTTPBodyConfig struct {
+	MaxSize int // bytes, [HTTPBodyMaxSizeDefault] if zero
+	// ContentTypes lists the media types to capture, JSON, form and plain text if empty.
+	// Types with the `+json` suffix are captured along with `application/json`.
+	ContentTypes []string
+	// Redact lists the fields masked with [HTTPMaskStrategy] in JSON and form bodies, common secrets if nil.
+	// A field is either a name matched at any depth (`password`)
+	// or a dot-separated path from the root where `*` matches any key or index (`cards.*.number`).
+	Redact []string
+}
+
+func (c HTTPBodyConfig) maxSize() int {
+	if c.MaxSize <= 0 {
+		return HTTPBodyMaxSizeDefault
+	}
+
+	return c.MaxSize
+}
+
+// mediaType returns the media type if it's allowed to be captured.
+func (c HTTPBodyConfig) mediaType(contentType, contentEncoding string) (string, bool) {
+	if contentEncoding != "" && !strings.EqualFold(contentEncoding, "identity") {
+		return "", false
+	}
+
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false
+	}
+
+	allowed := c.ContentTypes
+	if len(allowed) == 0 {
+		allowed = httpBodyContentTypesDefault
+	}
+
+	if strings.HasSuffix(mt, "+json") && slices.Contains(allowed, "application/json") {
+		return "application/json", true
+	}
+
+	return mt, slices.Contains(allowed, mt)
+}
+
+func (c HTTPBodyConfig) redactRules() [][]string {
+	redact := c.Redact
+	if redact == nil {
+		redact = httpBodyRedactDefault
+	}
+
+	rules := make([][]string, len(redact))
+	for i, r := range redact {
+		rules[i] = strings.Split(r, ".")
+	}
+
+	return rules
+}
+
+type httpBody struct {
+	Text      string
+	Truncated bool
+	Omitted   bool // the body can't be redacted, so it's not logged
+}
+
+func newHTTPBody(data []byte, truncated bool, mediaType string, cfg HTTPBodyConfig) *httpBody {
+	body := &httpBody{Truncated: truncated}
+
+	switch mediaType {
+	case "application/json":
+		text, ok := redactJSONBody(data, cfg.redactRules())
+		body.Text, body.Omitted = text, !ok
+	case "application/x-www-form-urlencoded":
+		rules := cfg.redactRules()
+		body.Text = maskQuery(string(data), func(name string) bool {
+			return httpQueryMasked(name) || redactMatch([]string{name}, rules)
+		})
+	default:
+		body.Text = string(data)
+	}
+
+	return body
+}
+
+func (b *httpBody) addTo(enc zapcore.ObjectEncoder) {
+	if !b.Omitted {
+		enc.AddString("body", b.Text)
+	}
+
+	if b.Truncated {
+		enc.AddBool("bodyTruncated", true)
+	}
+}
+
+// HTTPRequestWithBody is [HTTPRequest] with the body prefix of up to [HTTPBodyConfig.MaxSize] bytes.
+// The prefix is read from the request and put back, so the handler still reads the whole body.
+// Compressed bodies and the ones of other content types are not captured.
+func HTTPRequestWithBody(r *http.Request, cfg HTTPBodyConfig) zap.Field {
+	field := HTTPRequest(r)
+
+	m, _ := field.Interface.(*httpRequestMarshaler)
+	m.Body = captureHTTPBody(r, cfg)
+
+	return field
+}
+
+// FastHTTPRequestWithBody is the same as [HTTPRequestWithBody] but for fasthttp, streamed bodies are not captured.
+func FastHTTPRequestWithBody(r *fasthttp.Request, cfg HTTPBodyConfig) zap.Field {
+	field := FastHTTPRequest(r)
+
+	if r.IsBodyStream() {
+		return field
+	}
+
+	mt, ok := cfg.mediaType(string(r.Header.ContentType()), string(r.Header.ContentEncoding()))
+	if !ok {
+		return field
+	}
+
+	data := r.Body()
+	if len(data) == 0 {
+		return field
+	}
+
+	size := cfg.maxSize()
+
+	m, _ := field.Interface.(*httpRequestMarshaler)
+	m.Body = newHTTPBody(data[:min(len(data), size)], len(data) > size, mt, cfg)
+
+	return field
+}
+
+func captureHTTPBody(r *http.Request, cfg HTTPBodyConfig) *httpBody {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+
+	mt, ok := cfg.mediaType(r.Header.Get("Content-Type"), r.Header.Get("Content-Encoding"))
+	if !ok {
+		return nil
+	}
+
+	size := cfg.maxSize()
+	data, err := io.ReadAll(io.LimitReader(r.Body, int64(size)+1))
+
+	r.Body = &replayBody{Reader: io.MultiReader(bytes.NewReader(data), r.Body), Closer: r.Body}
+
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+
+	return newHTTPBody(data[:min(len(data), size)], len(data) > size, mt, cfg)
+}
+
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+// redactJSONBody returns the redacted JSON or false if it can't be parsed, e.g. when truncated.
+func redactJSONBody(data []byte, rules [][]string) (string, bool) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return "", false
+	}
+
+	res, err := json.Marshal(redactJSON(v, nil, rules))
+	if err != nil {
+		return "", false
+	}
+
+	return string(res), true
+}
+
+func redactJSON(v any, path []string, rules [][]string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			p := append(path[:len(path):len(path)], k)
+
+			if !redactMatch(p, rules) {
+				v[k] = redactJSON(child, p, rules)
+			} else if masked, keep := maskValue(jsonText(child)); keep {
+				v[k] = masked
+			} else {
+				delete(v, k)
+			}
+		}
+
+		return v
+	case []any:
+		res := v[:0]
+
+		for i, child := range v {
+			p := append(path[:len(path):len(path)], strconv.Itoa(i))
+
+			if !redactMatch(p, rules) {
+				res = append(res, redactJSON(child, p, rules))
+			} else if masked, keep := maskValue(jsonText(child)); keep {
+				res = append(res, masked)
+			}
+		}
+
+		return res
+	default:
+		return v
+	}
+}
+
+func redactMatch(path []string, rules [][]string) bool {
+	for _, rule := range rules {
+		if len(rule) == 1 {
+			if strings.EqualFold(rule[0], path[len(path)-1]) {
+				return true
+			}
+
+			continue
+		}
+
+		if len(rule) != len(path) {
+			continue
+		}
+
+		matched := true
+
+		for i, seg := range rule {
+			if seg != "*" && !strings.EqualFold(seg, path[i]) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func jsonText(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	b, _ := json.Marshal(v)
+
+	return string(b)
+}
@@ -0,0 +1,67 @@
+package tzap
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestHTTPRequestWithBody(t *testing.T) {
+	t.Parallel()
+
+	payload := `{"user":"bob","password":"qwerty","cards":[{"number":"4111111111111111","cvv":123}]}`
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	field := HTTPRequestWithBody(r, HTTPBodyConfig{Redact: []string{"password", "cvv", "cards.*.number"}})
+
+	m, ok := field.Interface.(*httpRequestMarshaler)
+	require.True(t, ok)
+	require.NotNil(t, m.Body)
+	assert.JSONEq(t, `{"user":"bob","password":"..*6*..","cards":[{"number":"..*16*..","cvv":"..*3*.."}]}`, m.Body.Text)
+	assert.False(t, m.Body.Truncated)
+
+	body, err := io.ReadAll(r.Body)
+	require.NoError(t, err)
+	assert.Equal(t, payload, string(body))
+}
+
+func TestHTTPRequestWithBody_Truncated(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"password":"qwerty"}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	m, ok := HTTPRequestWithBody(r, HTTPBodyConfig{MaxSize: 8}).Interface.(*httpRequestMarshaler)
+	require.True(t, ok)
+	require.NotNil(t, m.Body)
+	assert.True(t, m.Body.Truncated)
+	assert.True(t, m.Body.Omitted)
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("binary"))
+	r.Header.Set("Content-Type", "application/octet-stream")
+
+	m, ok = HTTPRequestWithBody(r, HTTPBodyConfig{}).Interface.(*httpRequestMarshaler)
+	require.True(t, ok)
+	assert.Nil(t, m.Body)
+}
+
+func TestFastHTTPRequestWithBody(t *testing.T) {
+	t.Parallel()
+
+	var r fasthttp.Request
+	r.Header.SetContentType("application/x-www-form-urlencoded")
+	r.SetBodyString("user=bob&token=abc&password=qwerty")
+
+	m, ok := FastHTTPRequestWithBody(&r, HTTPBodyConfig{}).Interface.(*httpRequestMarshaler)
+	require.True(t, ok)
+	require.NotNil(t, m.Body)
+	assert.Equal(t, "user=bob&token=..%2A3%2A..&password=..%2A6%2A..", m.Body.Text)
+}
@@ -1,10 +1,10 @@
 package tzap
 
 import (
+	"iter"
 	"maps"
 	"net/http"
 	"slices"
-	"strconv"
 
 	"github.com/valyala/fasthttp"
 	"go.uber.org/zap"
@@ -32,18 +32,11 @@ var (
 		"X-Auth":              {},
 		"X-Auth-Token":        {},
 		"X-Authorization":     {},
-	}
-	httpHeaderMask = func(vs ...string) []string {
-		res := make([]string, len(vs))
-
-		for i, v := range vs {
-			res[i] = "..*" + strconv.Itoa(len(v)) + "*.."
-		}
-
-		return res
+		"Set-Cookie":          {},
 	}
 
 	_ zapcore.ObjectMarshaler = (*httpRequestMarshaler)(nil)
+	_ zapcore.ObjectMarshaler = httpHeadersMarshaler(nil)
 )
 
 type httpRequestMarshaler struct {
@@ -51,7 +44,8 @@ type httpRequestMarshaler struct {
 	URL           string
 	Proto         string
 	ContentLength int64
-	Headers       map[string][]string
+	Headers       httpHeadersMarshaler
+	Body          *httpBody
 }
 
 func (m *httpRequestMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
@@ -59,71 +53,94 @@ func (m *httpRequestMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error
 	enc.AddString("url", m.URL)
 	enc.AddString("proto", m.Proto)
 	enc.AddInt64("contentLength", m.ContentLength)
+	_ = enc.AddObject("headers", m.Headers)
 
-	_ = enc.AddObject("headers", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
-		for k, vs := range m.Headers {
-			_ = enc.AddArray(k, zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
-				for _, v := range vs {
-					enc.AppendString(v)
-				}
+	if m.Body != nil {
+		m.Body.addTo(enc)
+	}
 
-				return nil
-			}))
-		}
+	return nil
+}
+
+type httpHeadersMarshaler map[string][]string
 
-		return nil
-	}))
+func (m httpHeadersMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for k, vs := range m {
+		_ = enc.AddArray(k, zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+			for _, v := range vs {
+				enc.AppendString(v)
+			}
+
+			return nil
+		}))
+	}
 
 	return nil
 }
 
-func HTTPRequest(r *http.Request) zap.Field {
-	headers := make(map[string][]string, len(r.Header))
+// httpHeaders copies the headers masking the ones from [HTTPRequestMaskHeaders]
+// and [HTTPRequestMaskHeaderPatterns] with [HTTPMaskStrategy].
+func httpHeaders(h http.Header) httpHeadersMarshaler {
+	headers := make(httpHeadersMarshaler, len(h))
 
-	for k, vs := range r.Header {
-		if _, mask := HTTPRequestMaskHeaders[k]; mask {
-			headers[k] = httpHeaderMask(vs...)
-		} else {
+	for k, vs := range h {
+		if !httpHeaderMasked(k) {
 			headers[k] = slices.Clone(vs)
+			continue
+		}
+
+		for _, v := range vs {
+			if masked, keep := maskValue(v); keep {
+				headers[k] = append(headers[k], masked)
+			}
+		}
+	}
+
+	return headers
+}
+
+// fastHTTPHeaders is the same as [httpHeaders] but for fasthttp headers.
+func fastHTTPHeaders(all iter.Seq2[[]byte, []byte], n int) httpHeadersMarshaler {
+	headers := make(httpHeadersMarshaler, n)
+
+	for key, value := range all {
+		k := string(key)
+		v := string(value)
+
+		if !httpHeaderMasked(k) {
+			headers[k] = append(headers[k], v)
+		} else if masked, keep := maskValue(v); keep {
+			headers[k] = append(headers[k], masked)
 		}
 	}
 
+	return headers
+}
+
+func HTTPRequest(r *http.Request) zap.Field {
 	return zap.Field{
 		Key:  KeyHTTPRequest,
 		Type: zapcore.ObjectMarshalerType,
 		Interface: &httpRequestMarshaler{
 			Method:        r.Method,
-			URL:           r.URL.String(),
+			URL:           maskURL(r.URL),
 			Proto:         r.Proto,
 			ContentLength: r.ContentLength,
-			Headers:       headers,
+			Headers:       httpHeaders(r.Header),
 		},
 	}
 }
 
 func FastHTTPRequest(r *fasthttp.Request) zap.Field {
-	headers := make(map[string][]string, r.Header.Len())
-
-	r.Header.VisitAll(func(key, value []byte) {
-		k := string(key)
-		v := string(value)
-
-		if _, mask := HTTPRequestMaskHeaders[k]; mask {
-			headers[k] = append(headers[k], httpHeaderMask(v)...)
-		} else {
-			headers[k] = append(headers[k], v)
-		}
-	})
-
 	return zap.Field{
 		Key:  KeyHTTPRequest,
 		Type: zapcore.ObjectMarshalerType,
 		Interface: &httpRequestMarshaler{
 			Method:        string(r.Header.Method()),
-			URL:           r.URI().String(),
+			URL:           maskURLString(r.URI().String()),
 			Proto:         string(r.Header.Protocol()),
 			ContentLength: int64(r.Header.ContentLength()),
-			Headers:       headers,
+			Headers:       fastHTTPHeaders(r.Header.All(), r.Header.Len()),
 		},
 	}
 }
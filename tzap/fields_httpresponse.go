@@ -0,0 +1,249 @@
+package tzap
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	_ http.ResponseWriter = (*ResponseWriter)(nil)
+	_ http.Flusher        = (*ResponseWriter)(nil)
+	_ http.Hijacker       = (*ResponseWriter)(nil)
+	_ io.ReaderFrom       = (*ResponseWriter)(nil)
+
+	_ zapcore.ObjectMarshaler = (*httpResponseMarshaler)(nil)
+	_ zapcore.ObjectMarshaler = (*httpExchangeMarshaler)(nil)
+)
+
+// ResponseWriter records the status and the number of bytes written to the wrapped writer.
+// Optional interfaces of the wrapped writer are reachable with [http.ResponseController].
+type ResponseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+func NewResponseWriter(w http.ResponseWriter) *ResponseWriter {
+	if rw, ok := w.(*ResponseWriter); ok {
+		return rw
+	}
+
+	return &ResponseWriter{ResponseWriter: w}
+}
+
+func (w *ResponseWriter) WriteHeader(status int) {
+	// informational responses are followed by the final one, except for protocol switching
+	if w.status == 0 && (status >= http.StatusOK || status == http.StatusSwitchingProtocols) {
+		w.status = status
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *ResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(p)
+	w.size += int64(n)
+
+	return n, err
+}
+
+func (w *ResponseWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// ReadFrom keeps the sendfile path of the wrapped writer, if any.
+func (w *ResponseWriter) ReadFrom(r io.Reader) (int64, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := io.Copy(w.ResponseWriter, r)
+	w.size += n
+
+	return n, err
+}
+
+// Hijack takes over the connection, which is recorded as [http.StatusSwitchingProtocols] unless a status was sent.
+func (w *ResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+
+	return conn, rw, err
+}
+
+func (w *ResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Status returns the status sent to the client, [http.StatusOK] if nothing was written yet.
+func (w *ResponseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+
+	return w.status
+}
+
+// Size returns the number of body bytes written.
+func (w *ResponseWriter) Size() int64 {
+	return w.size
+}
+
+type httpResponseMarshaler struct {
+	Status  int
+	Size    int64
+	Latency time.Duration
+	Headers httpHeadersMarshaler
+}
+
+func (m *httpResponseMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt("status", m.Status)
+	enc.AddInt64("size", m.Size)
+	enc.AddDuration("latency", m.Latency)
+	_ = enc.AddObject("headers", m.Headers)
+
+	return nil
+}
+
+// httpExchangeMarshaler follows the GCP LogEntry HttpRequest shape, which is understood by Elastic as well.
+type httpExchangeMarshaler struct {
+	RequestMethod string
+	RequestURL    string
+	RequestSize   int64
+	Status        int
+	ResponseSize  int64
+	UserAgent     string
+	RemoteIP      string
+	ServerIP      string
+	Referer       string
+	Latency       time.Duration
+	Protocol      string
+}
+
+func (m *httpExchangeMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("requestMethod", m.RequestMethod)
+	enc.AddString("requestUrl", m.RequestURL)
+	enc.AddString("requestSize", strconv.FormatInt(m.RequestSize, 10))
+	enc.AddInt("status", m.Status)
+	enc.AddString("responseSize", strconv.FormatInt(m.ResponseSize, 10))
+	enc.AddString("userAgent", m.UserAgent)
+	enc.AddString("remoteIp", m.RemoteIP)
+
+	if m.ServerIP != "" {
+		enc.AddString("serverIp", m.ServerIP)
+	}
+
+	if m.Referer != "" {
+		enc.AddString("referer", m.Referer)
+	}
+
+	enc.AddString("latency", strconv.FormatFloat(m.Latency.Seconds(), 'f', -1, 64)+"s")
+	enc.AddString("protocol", m.Protocol)
+
+	return nil
+}
+
+func HTTPResponse(w *ResponseWriter, latency time.Duration) zap.Field {
+	return zap.Field{
+		Key:  KeyHTTPResponse,
+		Type: zapcore.ObjectMarshalerType,
+		Interface: &httpResponseMarshaler{
+			Status:  w.Status(),
+			Size:    w.Size(),
+			Latency: latency,
+			Headers: httpHeaders(w.Header()),
+		},
+	}
+}
+
+func FastHTTPResponse(r *fasthttp.Response, latency time.Duration) zap.Field {
+	return zap.Field{
+		Key:  KeyHTTPResponse,
+		Type: zapcore.ObjectMarshalerType,
+		Interface: &httpResponseMarshaler{
+			Status:  r.StatusCode(),
+			Size:    fastHTTPResponseSize(r),
+			Latency: latency,
+			Headers: fastHTTPHeaders(r.Header.All(), r.Header.Len()),
+		},
+	}
+}
+
+// HTTPExchange describes both the request and the response in a single `httpRequest` object.
+func HTTPExchange(r *http.Request, w *ResponseWriter, latency time.Duration) zap.Field {
+	m := &httpExchangeMarshaler{
+		RequestMethod: r.Method,
+		RequestURL:    maskURL(r.URL),
+		RequestSize:   max(r.ContentLength, 0),
+		Status:        w.Status(),
+		ResponseSize:  w.Size(),
+		UserAgent:     r.UserAgent(),
+		RemoteIP:      hostIP(r.RemoteAddr),
+		Referer:       maskURLString(r.Referer()),
+		Latency:       latency,
+		Protocol:      r.Proto,
+	}
+
+	if addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+		m.ServerIP = hostIP(addr.String())
+	}
+
+	return zap.Field{Key: KeyHTTPExchange, Type: zapcore.ObjectMarshalerType, Interface: m}
+}
+
+// FastHTTPExchange is the same as [HTTPExchange] for fasthttp, the response must be already filled.
+func FastHTTPExchange(ctx *fasthttp.RequestCtx, latency time.Duration) zap.Field {
+	return zap.Field{
+		Key:  KeyHTTPExchange,
+		Type: zapcore.ObjectMarshalerType,
+		Interface: &httpExchangeMarshaler{
+			RequestMethod: string(ctx.Method()),
+			RequestURL:    maskURLString(ctx.URI().String()),
+			RequestSize:   int64(max(ctx.Request.Header.ContentLength(), 0)),
+			Status:        ctx.Response.StatusCode(),
+			ResponseSize:  fastHTTPResponseSize(&ctx.Response),
+			UserAgent:     string(ctx.UserAgent()),
+			RemoteIP:      ctx.RemoteIP().String(),
+			ServerIP:      ctx.LocalIP().String(),
+			Referer:       maskURLString(string(ctx.Referer())),
+			Latency:       latency,
+			Protocol:      string(ctx.Request.Header.Protocol()),
+		},
+	}
+}
+
+// fastHTTPResponseSize never reads a body stream, which might be endless like SSE: its size is the content length,
+// or zero if it isn't known in advance.
+func fastHTTPResponseSize(r *fasthttp.Response) int64 {
+	if r.IsBodyStream() {
+		return int64(max(r.Header.ContentLength(), 0))
+	}
+
+	return int64(len(r.Body()))
+}
+
+func hostIP(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
@@ -0,0 +1,160 @@
+package tzap
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestResponseWriter(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	w := NewResponseWriter(rec)
+	assert.Same(t, w, NewResponseWriter(w))
+
+	w.WriteHeader(http.StatusCreated)
+
+	_, err := w.Write([]byte("hello"))
+	require.NoError(t, err)
+	require.NoError(t, http.NewResponseController(w).Flush())
+
+	assert.Equal(t, http.StatusCreated, w.Status())
+	assert.EqualValues(t, 5, w.Size())
+	assert.True(t, rec.Flushed)
+}
+
+func TestResponseWriter_ReadFrom(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	w := NewResponseWriter(rec)
+
+	n, err := io.Copy(w, strings.NewReader("hello"))
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 5, n)
+	assert.EqualValues(t, 5, w.Size())
+	assert.Equal(t, http.StatusOK, w.Status())
+	assert.Equal(t, "hello", rec.Body.String())
+}
+
+func TestResponseWriter_Hijack(t *testing.T) {
+	t.Parallel()
+
+	status := make(chan int, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		w := NewResponseWriter(rw)
+
+		conn, buf, err := http.NewResponseController(w).Hijack()
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		defer conn.Close()
+
+		_, _ = buf.WriteString("HTTP/1.1 101 Switching Protocols\r\n\r\n")
+		_ = buf.Flush()
+
+		status <- w.Status()
+	}))
+	defer srv.Close()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	assert.Equal(t, http.StatusSwitchingProtocols, <-status)
+
+	_, _, err = http.NewResponseController(NewResponseWriter(httptest.NewRecorder())).Hijack()
+	require.ErrorIs(t, err, http.ErrNotSupported)
+}
+
+func TestHTTPExchange(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodPost, "/path?q=1", nil)
+	r.Header.Set("User-Agent", "test")
+
+	w := NewResponseWriter(httptest.NewRecorder())
+	w.Header().Set("Set-Cookie", "secret")
+	_, err := w.Write([]byte("hello"))
+	require.NoError(t, err)
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	zap.New(core).Info("request", HTTPExchange(r, w, 1500*time.Millisecond), HTTPResponse(w, time.Second))
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, map[string]any{
+		KeyHTTPExchange: map[string]any{
+			"requestMethod": http.MethodPost,
+			"requestUrl":    "/path?q=1",
+			"requestSize":   "0",
+			"status":        http.StatusOK,
+			"responseSize":  "5",
+			"userAgent":     "test",
+			"remoteIp":      "192.0.2.1",
+			"latency":       "1.5s",
+			"protocol":      "HTTP/1.1",
+		},
+		KeyHTTPResponse: map[string]any{
+			"status":  http.StatusOK,
+			"size":    int64(5),
+			"latency": time.Second,
+			"headers": map[string]any{
+				"Content-Type": []any{"text/plain; charset=utf-8"},
+				"Set-Cookie":   []any{"..*6*.."},
+			},
+		},
+	}, entries[0].ContextMap())
+}
+
+// unreadBody is a response body stream that must not be read while logging.
+type unreadBody struct {
+	t *testing.T
+}
+
+func (b unreadBody) Read([]byte) (int, error) {
+	b.t.Error("body stream is read")
+	return 0, io.EOF
+}
+
+func TestFastHTTPResponse_BodyStream(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	log := zap.New(core)
+
+	var r fasthttp.Response
+
+	r.SetBodyStream(unreadBody{t: t}, 5)
+	log.Info("sized", FastHTTPResponse(&r, time.Second))
+
+	r.SetBodyStream(unreadBody{t: t}, -1)
+	log.Info("chunked", FastHTTPResponse(&r, time.Second))
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+
+	for i, size := range []int64{5, 0} {
+		resp, ok := entries[i].ContextMap()[KeyHTTPResponse].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, size, resp["size"])
+	}
+}
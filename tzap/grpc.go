@@ -0,0 +1,97 @@
+package tzap
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	MetadataRequestID = "x-request-id"
+)
+
+var (
+	_ grpc.ServerStream = (*contextServerStream)(nil)
+)
+
+// GRPCUnaryServerContext returns an interceptor putting the call-scoped logger into the context, see [FromContext].
+//
+// The logger is derived from `log` (the global one if nil) with the request ID and the full method added.
+// The request ID is taken from the [MetadataRequestID] incoming metadata or generated.
+func GRPCUnaryServerContext(log *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(grpcServerContext(ctx, log, info.FullMethod), req)
+	}
+}
+
+// GRPCStreamServerContext is the same as [GRPCUnaryServerContext] but for streams.
+func GRPCStreamServerContext(log *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: grpcServerContext(ss.Context(), log, info.FullMethod)})
+	}
+}
+
+// GRPCUnaryClientContext returns an interceptor passing the request ID of the context in the [MetadataRequestID]
+// outgoing metadata.
+//
+// A call made outside of a request scope starts a new one:
+// a request ID is generated and the context logger gets it along with the full method.
+func GRPCUnaryClientContext() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+	) error {
+		return invoker(grpcClientContext(ctx, method), method, req, reply, cc, opts...)
+	}
+}
+
+// GRPCStreamClientContext is the same as [GRPCUnaryClientContext] but for streams.
+func GRPCStreamClientContext() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		return streamer(grpcClientContext(ctx, method), desc, cc, method, opts...)
+	}
+}
+
+func grpcServerContext(ctx context.Context, log *zap.Logger, method string) context.Context {
+	var id string
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vs := md.Get(MetadataRequestID); len(vs) > 0 {
+			id = vs[0]
+		}
+	}
+
+	id = requestIDOrNew(id)
+
+	if log == nil {
+		log = zap.L()
+	}
+
+	ctx = WithRequestID(ctx, id)
+
+	return WithLogger(ctx, log.With(zap.String(KeyRequestID, id), zap.String(KeyMethod, method)))
+}
+
+func grpcClientContext(ctx context.Context, method string) context.Context {
+	id := RequestID(ctx)
+
+	if id == "" {
+		id = NewRequestID()
+		ctx = WithRequestID(ctx, id)
+		ctx = WithContext(ctx, zap.String(KeyRequestID, id), zap.String(KeyMethod, method))
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, MetadataRequestID, id)
+}
+
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context //nolint:containedctx // overrides the stream context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
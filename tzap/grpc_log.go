@@ -0,0 +1,138 @@
+package tzap
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	GRPCLogMessageDefault = "grpc call"
+)
+
+type GRPCLogOptions struct {
+	Message string                              // [GRPCLogMessageDefault] if empty
+	Level   func(code codes.Code) zapcore.Level // [GRPCCodeLevel] if nil
+}
+
+// GRPCCodeLevel is the default level policy: error for server faults, warn for client faults, info otherwise.
+func GRPCCodeLevel(code codes.Code) zapcore.Level {
+	switch code {
+	case codes.OK:
+		return zapcore.InfoLevel
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.WarnLevel
+	}
+}
+
+type grpcLog struct {
+	log     *zap.Logger
+	message string
+	level   func(code codes.Code) zapcore.Level
+}
+
+func newGRPCLog(log *zap.Logger, opts GRPCLogOptions) *grpcLog {
+	l := &grpcLog{log: log, message: opts.Message, level: opts.Level}
+
+	if l.message == "" {
+		l.message = GRPCLogMessageDefault
+	}
+
+	if l.level == nil {
+		l.level = GRPCCodeLevel
+	}
+
+	return l
+}
+
+// write logs the call with the logger of the context unless an explicit one is set,
+// in which case the request ID and the trace of the context are added to it.
+func (l *grpcLog) write(ctx context.Context, err error, call zap.Field) {
+	log := l.log
+
+	var fields []zap.Field
+
+	if log == nil {
+		log = FromContext(ctx)
+	} else {
+		fields = contextFields(ctx)
+	}
+
+	if ce := log.Check(l.level(status.Code(err)), l.message); ce != nil {
+		fields = append(fields, call)
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+		}
+
+		ce.Write(fields...)
+	}
+}
+
+// GRPCUnaryServerLog returns an interceptor writing an entry per call with [GRPCServerCall].
+// If `log` is nil, the call-scoped logger is used, see [GRPCUnaryServerContext].
+func GRPCUnaryServerLog(log *zap.Logger, options GRPCLogOptions) grpc.UnaryServerInterceptor {
+	l := newGRPCLog(log, options)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		l.write(ctx, err, GRPCServerCall(ctx, info.FullMethod, err, time.Since(start)))
+
+		return resp, err
+	}
+}
+
+// GRPCStreamServerLog is the same as [GRPCUnaryServerLog] but for streams.
+func GRPCStreamServerLog(log *zap.Logger, options GRPCLogOptions) grpc.StreamServerInterceptor {
+	l := newGRPCLog(log, options)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		l.write(ss.Context(), err, GRPCServerCall(ss.Context(), info.FullMethod, err, time.Since(start)))
+
+		return err
+	}
+}
+
+// GRPCUnaryClientLog returns an interceptor writing an entry per call with [GRPCClientCall].
+// If `log` is nil, the context logger is used, see [FromContext].
+func GRPCUnaryClientLog(log *zap.Logger, options GRPCLogOptions) grpc.UnaryClientInterceptor {
+	l := newGRPCLog(log, options)
+
+	return func(
+		ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+	) error {
+		p := new(peer.Peer)
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Peer(p))...)
+		l.write(ctx, err, GRPCClientCall(ctx, method, p, err, time.Since(start)))
+
+		return err
+	}
+}
+
+// GRPCStreamClientLog is the same as [GRPCUnaryClientLog] but for streams, the entry is written once the stream is
+// established.
+func GRPCStreamClientLog(log *zap.Logger, options GRPCLogOptions) grpc.StreamClientInterceptor {
+	l := newGRPCLog(log, options)
+
+	return func(
+		ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		p := new(peer.Peer)
+		start := time.Now()
+		cs, err := streamer(ctx, desc, cc, method, append(opts, grpc.Peer(p))...)
+		l.write(ctx, err, GRPCClientCall(ctx, method, p, err, time.Since(start)))
+
+		return cs, err
+	}
+}
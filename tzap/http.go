@@ -0,0 +1,55 @@
+package tzap
+
+import (
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+const (
+	HeaderRequestID = "X-Request-Id"
+)
+
+// HTTPContext returns a net/http middleware putting the request-scoped logger into the request context,
+// see [FromContext].
+//
+// The logger is derived from `log` (the global one if nil) with the request ID and the method added.
+// The request ID is taken from the [HeaderRequestID] header or generated, and it's sent back in the same header.
+func HTTPContext(log *zap.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := requestIDOrNew(r.Header.Get(HeaderRequestID))
+			w.Header().Set(HeaderRequestID, id)
+
+			ctx := WithRequestID(r.Context(), id)
+			ctx = WithLogger(ctx, httpContextLogger(log, id, r.Method))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// FastHTTPContext is the same as [HTTPContext] but for fasthttp.
+// The values are stored as user values of [fasthttp.RequestCtx], so it can be passed to [FromContext] as is.
+func FastHTTPContext(log *zap.Logger) func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			id := requestIDOrNew(string(ctx.Request.Header.Peek(HeaderRequestID)))
+			ctx.Response.Header.Set(HeaderRequestID, id)
+
+			ctx.SetUserValue(ctxRequestIDKey{}, id)
+			ctx.SetUserValue(ctxLoggerKey{}, httpContextLogger(log, id, string(ctx.Method())))
+
+			next(ctx)
+		}
+	}
+}
+
+func httpContextLogger(log *zap.Logger, requestID, method string) *zap.Logger {
+	if log == nil {
+		log = zap.L()
+	}
+
+	return log.With(zap.String(KeyRequestID, requestID), zap.String(KeyMethod, method))
+}
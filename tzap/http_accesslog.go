@@ -0,0 +1,168 @@
+package tzap
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	AccessLogMessageDefault = "request"
+)
+
+type AccessLogOptions struct {
+	Message   string                         // [AccessLogMessageDefault] if empty
+	Level     func(status int) zapcore.Level // [AccessLogLevel] if nil
+	SkipPaths []string                       // never logged, e.g. health probes
+	// Sample maps paths to N so that only each Nth request to the path is logged.
+	// Entries above the info level are logged regardless.
+	Sample map[string]uint64
+	// Body enables the request body capture, see [HTTPRequestWithBody].
+	Body *HTTPBodyConfig
+}
+
+// AccessLogLevel is the default level policy: error for 5xx, warn for 4xx, info otherwise.
+func AccessLogLevel(status int) zapcore.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return zapcore.ErrorLevel
+	case status >= http.StatusBadRequest:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+type accessLog struct {
+	log     *zap.Logger
+	message string
+	level   func(status int) zapcore.Level
+	skip    map[string]struct{}
+	sample  map[string]*accessLogSampler
+	body    *HTTPBodyConfig
+}
+
+type accessLogSampler struct {
+	n     uint64
+	count atomic.Uint64
+}
+
+func newAccessLog(log *zap.Logger, opts AccessLogOptions) *accessLog {
+	l := &accessLog{
+		log:     log,
+		message: opts.Message,
+		level:   opts.Level,
+		skip:    make(map[string]struct{}, len(opts.SkipPaths)),
+		sample:  make(map[string]*accessLogSampler, len(opts.Sample)),
+		body:    opts.Body,
+	}
+
+	if l.message == "" {
+		l.message = AccessLogMessageDefault
+	}
+
+	if l.level == nil {
+		l.level = AccessLogLevel
+	}
+
+	for _, path := range opts.SkipPaths {
+		l.skip[path] = struct{}{}
+	}
+
+	for path, n := range opts.Sample {
+		if n > 1 {
+			l.sample[path] = &accessLogSampler{n: n}
+		}
+	}
+
+	return l
+}
+
+func (l *accessLog) skipped(path string) bool {
+	_, ok := l.skip[path]
+	return ok
+}
+
+// check returns the entry to write or nil if the request must not be logged, along with the fields of the context
+// the explicit logger lacks, unlike the request-scoped one.
+func (l *accessLog) check(ctx context.Context, path string, status int) (*zapcore.CheckedEntry, []zap.Field) {
+	lvl := l.level(status)
+
+	if s, ok := l.sample[path]; ok && lvl <= zapcore.InfoLevel && (s.count.Add(1)-1)%s.n != 0 {
+		return nil, nil
+	}
+
+	if l.log == nil {
+		return FromContext(ctx).Check(lvl, l.message), nil
+	}
+
+	return l.log.Check(lvl, l.message), contextFields(ctx)
+}
+
+// AccessLog returns a net/http middleware writing an entry per request with [HTTPRequest] and [HTTPResponse].
+// If `log` is nil, the request-scoped logger is used, see [HTTPContext].
+func AccessLog(log *zap.Logger, opts AccessLogOptions) func(next http.Handler) http.Handler {
+	l := newAccessLog(log, opts)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if l.skipped(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var req zap.Field
+			if l.body != nil {
+				// the body must be captured before the handler reads it
+				req = HTTPRequestWithBody(r, *l.body)
+			}
+
+			start := time.Now()
+			rw := NewResponseWriter(w)
+
+			next.ServeHTTP(rw, r)
+
+			if ce, fields := l.check(r.Context(), r.URL.Path, rw.Status()); ce != nil {
+				if l.body == nil {
+					req = HTTPRequest(r)
+				}
+
+				ce.Write(append(fields, req, HTTPResponse(rw, time.Since(start)))...)
+			}
+		})
+	}
+}
+
+// FastHTTPAccessLog is the same as [AccessLog] but for fasthttp, see [FastHTTPContext].
+func FastHTTPAccessLog(log *zap.Logger, opts AccessLogOptions) func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	l := newAccessLog(log, opts)
+
+	return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			path := string(ctx.Path())
+
+			if l.skipped(path) {
+				next(ctx)
+				return
+			}
+
+			start := time.Now()
+
+			next(ctx)
+
+			if ce, fields := l.check(ctx, path, ctx.Response.StatusCode()); ce != nil {
+				req := FastHTTPRequest(&ctx.Request)
+				if l.body != nil {
+					req = FastHTTPRequestWithBody(&ctx.Request, *l.body)
+				}
+
+				ce.Write(append(fields, req, FastHTTPResponse(&ctx.Response, time.Since(start)))...)
+			}
+		}
+	}
+}
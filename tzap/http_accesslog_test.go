@@ -0,0 +1,109 @@
+package tzap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAccessLog(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	h := AccessLog(zap.New(core), AccessLogOptions{
+		SkipPaths: []string{"/health"},
+		Sample:    map[string]uint64{"/hot": 3},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("fail") {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+
+	for _, target := range []string{"/health", "/hot", "/hot", "/hot", "/hot", "/hot?fail", "/other"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 4)
+
+	for _, e := range entries {
+		assert.Equal(t, AccessLogMessageDefault, e.Message)
+		assert.Contains(t, e.ContextMap(), KeyHTTPRequest)
+		assert.Contains(t, e.ContextMap(), KeyHTTPResponse)
+	}
+
+	assert.Equal(t, zapcore.InfoLevel, entries[0].Level)
+	assert.Equal(t, zapcore.InfoLevel, entries[1].Level)
+	assert.Equal(t, zapcore.ErrorLevel, entries[2].Level)
+	assert.Equal(t, zapcore.InfoLevel, entries[3].Level)
+}
+
+func TestAccessLog_ContextFields(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	h := HTTPContext(nil)(AccessLog(zap.New(core), AccessLogOptions{})(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/traced", nil).WithContext(testSpanContext(t))
+	req.Header.Set(HeaderRequestID, "req-1")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "req-1", entries[0].ContextMap()[KeyRequestID])
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", entries[0].ContextMap()[KeyTraceID])
+}
+
+func TestFastHTTPAccessLog(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	h := FastHTTPContext(zap.New(core))(FastHTTPAccessLog(nil, AccessLogOptions{})(func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusNotFound)
+	}))
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetRequestURI("/missing")
+	h(ctx)
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 1)
+	assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+	assert.Contains(t, entries[0].ContextMap(), KeyRequestID)
+
+	resp, ok := entries[0].ContextMap()[KeyHTTPResponse].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, fasthttp.StatusNotFound, resp["status"])
+}
+
+func TestFastHTTPAccessLog_BodyStream(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	h := FastHTTPAccessLog(zap.New(core), AccessLogOptions{})(func(ctx *fasthttp.RequestCtx) {
+		ctx.SetBodyStream(unreadBody{t: t}, 5)
+	})
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.SetRequestURI("/events")
+	h(ctx)
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 1)
+
+	resp, ok := entries[0].ContextMap()[KeyHTTPResponse].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, int64(5), resp["size"])
+}
@@ -0,0 +1,166 @@
+package tzap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"maps"
+	"net/textproto"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MaskStrategy defines how sensitive values are hidden in the logs.
+type MaskStrategy int
+
+const (
+	MaskLength MaskStrategy = iota // replace with the value length: `..*6*..`
+	MaskHash                       // replace with a short SHA-256 hash to correlate equal values
+	MaskRemove                     // drop the header, query parameter or password completely
+)
+
+const maskHashLen = 8
+
+var (
+	// HTTPMaskStrategy is applied to all the masked HTTP headers, query parameters and URL passwords.
+	HTTPMaskStrategy = MaskLength
+
+	// HTTPRequestMaskHeaderPatterns mask headers with matching names in addition to [HTTPRequestMaskHeaders].
+	HTTPRequestMaskHeaderPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)(token|secret|passw(or)?d|api-?key|session)`),
+	}
+
+	// HTTPRequestMaskQuery lists query parameters masked in logged URLs, matched case-insensitively.
+	HTTPRequestMaskQuery        = maps.Clone(httpRequestMaskQueryDefault)
+	httpRequestMaskQueryDefault = map[string]struct{}{
+		"access_token":  {},
+		"api_key":       {},
+		"apikey":        {},
+		"client_secret": {},
+		"code":          {},
+		"id_token":      {},
+		"key":           {},
+		"password":      {},
+		"refresh_token": {},
+		"secret":        {},
+		"sig":           {},
+		"signature":     {},
+		"token":         {},
+	}
+)
+
+// Mask returns the masked value or false if it must be removed.
+func (s MaskStrategy) Mask(v string) (string, bool) {
+	switch s {
+	case MaskRemove:
+		return "", false
+	case MaskHash:
+		sum := sha256.Sum256([]byte(v))
+		return "sha256:" + hex.EncodeToString(sum[:maskHashLen]), true
+	default:
+		return "..*" + strconv.Itoa(len(v)) + "*..", true
+	}
+}
+
+func maskValue(v string) (string, bool) {
+	return HTTPMaskStrategy.Mask(v)
+}
+
+// httpHeaderMasked reports whether the header must be masked regardless of the name case.
+func httpHeaderMasked(k string) bool {
+	if _, ok := HTTPRequestMaskHeaders[textproto.CanonicalMIMEHeaderKey(k)]; ok {
+		return true
+	}
+
+	for name := range HTTPRequestMaskHeaders {
+		if strings.EqualFold(name, k) {
+			return true
+		}
+	}
+
+	for _, re := range HTTPRequestMaskHeaderPatterns {
+		if re.MatchString(k) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func httpQueryMasked(k string) bool {
+	if _, ok := HTTPRequestMaskQuery[k]; ok {
+		return true
+	}
+
+	for name := range HTTPRequestMaskQuery {
+		if strings.EqualFold(name, k) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// maskURL returns the URL string with the password and the parameters from [HTTPRequestMaskQuery] masked.
+// The order of the query parameters is kept.
+func maskURL(u *url.URL) string {
+	if u.RawQuery == "" && u.User == nil {
+		return u.String()
+	}
+
+	m := *u
+
+	if pass, ok := u.User.Password(); ok {
+		if masked, keep := maskValue(pass); keep {
+			m.User = url.UserPassword(u.User.Username(), masked)
+		} else {
+			m.User = url.User(u.User.Username())
+		}
+	}
+
+	m.RawQuery = maskQuery(u.RawQuery, httpQueryMasked)
+
+	return m.String()
+}
+
+// maskURLString is the same as [maskURL] for raw URLs, unparsable ones are logged without the query.
+func maskURLString(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		s, _, _ = strings.Cut(s, "?")
+		return s
+	}
+
+	return maskURL(u)
+}
+
+// maskQuery masks the values of the URL-encoded parameters accepted by `masked`.
+func maskQuery(raw string, masked func(name string) bool) string {
+	if raw == "" {
+		return raw
+	}
+
+	params := strings.Split(raw, "&")
+	res := params[:0]
+
+	for _, param := range params {
+		k, v, _ := strings.Cut(param, "=")
+
+		name, err := url.QueryUnescape(k)
+		if err != nil {
+			name = k
+		}
+
+		if !masked(name) {
+			res = append(res, param)
+			continue
+		}
+
+		if masked, keep := maskValue(v); keep {
+			res = append(res, k+"="+url.QueryEscape(masked))
+		}
+	}
+
+	return strings.Join(res, "&")
+}
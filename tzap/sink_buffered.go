@@ -0,0 +1,260 @@
+package tzap
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	BufferSizeDefault          = 256 << 10
+	BufferFlushIntervalDefault = time.Second
+)
+
+var (
+	ErrBufferStopped = errors.New("buffer is stopped")
+
+	_ zapcore.WriteSyncer = (*BufferedWriteSyncer)(nil)
+	_ zapcore.Core        = (*bufferedCore)(nil)
+)
+
+type BufferedConfig struct {
+	Size          int           // max bytes held in memory, [BufferSizeDefault] if zero
+	FlushInterval time.Duration // [BufferFlushIntervalDefault] if zero
+	Async         bool          // never write to the underlying syncer from the logging goroutine
+	Drop          bool          // drop entries instead of blocking when async buffer is full
+}
+
+// BufferedWriteSyncer accumulates writes in a bounded buffer and flushes them
+// periodically, when the buffer is full and on Sync.
+//
+// In sync mode a full buffer is flushed by the writing goroutine, and entries larger than the buffer are written through.
+// In async mode it's flushed in the background while the writer either blocks or drops the entry,
+// dropped entries are counted. An entry larger than the buffer waits for it to be empty and is held alone.
+type BufferedWriteSyncer struct {
+	ws  zapcore.WriteSyncer
+	cfg BufferedConfig
+
+	mu     sync.Mutex
+	space  *sync.Cond // signaled when the buffer is flushed or stopped
+	buf    []byte
+	closed bool
+
+	flushMu sync.Mutex // serializes writes to ws
+	spare   []byte     // guarded by flushMu
+
+	dropped  atomic.Uint64
+	wake     chan struct{}
+	stop     chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+// NewBufferedWriteSyncer wraps the syncer and starts the background flusher.
+// Stop must be called to release it.
+func NewBufferedWriteSyncer(ws zapcore.WriteSyncer, cfg BufferedConfig) *BufferedWriteSyncer {
+	if cfg.Size <= 0 {
+		cfg.Size = BufferSizeDefault
+	}
+
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = BufferFlushIntervalDefault
+	}
+
+	s := &BufferedWriteSyncer{
+		ws:      ws,
+		cfg:     cfg,
+		buf:     make([]byte, 0, cfg.Size),
+		spare:   make([]byte, 0, cfg.Size),
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	s.space = sync.NewCond(&s.mu)
+
+	go s.run()
+
+	return s
+}
+
+func (s *BufferedWriteSyncer) Write(p []byte) (int, error) {
+	if len(p) > s.cfg.Size && !s.cfg.Async {
+		return s.writeThrough(p)
+	}
+
+	s.mu.Lock()
+
+	for !s.closed && len(s.buf) > 0 && len(s.buf)+len(p) > s.cfg.Size {
+		if !s.cfg.Async {
+			s.mu.Unlock()
+
+			if err := s.flush(); err != nil {
+				return 0, err
+			}
+
+			s.mu.Lock()
+
+			continue
+		}
+
+		s.notify()
+
+		if s.cfg.Drop {
+			s.mu.Unlock()
+			s.dropped.Add(1)
+
+			return len(p), nil
+		}
+
+		s.space.Wait()
+	}
+
+	if s.closed {
+		s.mu.Unlock()
+		return 0, ErrBufferStopped
+	}
+
+	s.buf = append(s.buf, p...)
+
+	if s.cfg.Async && len(s.buf) > s.cfg.Size/2 {
+		s.notify()
+	}
+
+	s.mu.Unlock()
+
+	return len(p), nil
+}
+
+// writeThrough writes the entry that would never fit right after the buffered ones.
+func (s *BufferedWriteSyncer) writeThrough(p []byte) (int, error) {
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+
+	if closed {
+		return 0, ErrBufferStopped
+	}
+
+	if err := s.flush(); err != nil {
+		return 0, err
+	}
+
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	return s.ws.Write(p)
+}
+
+// Sync flushes the buffer and syncs the underlying syncer.
+func (s *BufferedWriteSyncer) Sync() error {
+	return errors.Join(s.flush(), s.ws.Sync())
+}
+
+// Dropped returns the number of entries dropped since the creation.
+func (s *BufferedWriteSyncer) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Stop flushes the buffer and stops the background flusher.
+// Writes after Stop fail with [ErrBufferStopped], the blocked ones included.
+func (s *BufferedWriteSyncer) Stop() error {
+	s.stopOnce.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		s.space.Broadcast()
+		s.mu.Unlock()
+
+		close(s.stop)
+	})
+	<-s.stopped
+
+	return s.flush()
+}
+
+func (s *BufferedWriteSyncer) run() {
+	defer close(s.stopped)
+
+	t := time.NewTicker(s.cfg.FlushInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-t.C:
+		case <-s.wake:
+		}
+
+		_ = s.flush() // the error is reported on the next Sync
+	}
+}
+
+// notify wakes the background flusher up, must be called with mu held.
+func (s *BufferedWriteSyncer) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *BufferedWriteSyncer) flush() error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	data := s.buf
+	s.buf = s.spare[:0]
+	s.space.Broadcast()
+	s.mu.Unlock()
+
+	s.spare = data[:0]
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	_, err := s.ws.Write(data)
+
+	return err
+}
+
+// bufferedCore reports the entries dropped by the [BufferedWriteSyncer] as a warning on each Sync.
+type bufferedCore struct {
+	zapcore.Core
+	ws       *BufferedWriteSyncer
+	reported *atomic.Uint64
+}
+
+func newBufferedCore(core zapcore.Core, ws *BufferedWriteSyncer) *bufferedCore {
+	return &bufferedCore{Core: core, ws: ws, reported: new(atomic.Uint64)}
+}
+
+func (c *bufferedCore) With(fields []zapcore.Field) zapcore.Core {
+	return &bufferedCore{Core: c.Core.With(fields), ws: c.ws, reported: c.reported}
+}
+
+func (c *bufferedCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+func (c *bufferedCore) Sync() error {
+	dropped := c.ws.Dropped()
+	reported := c.reported.Swap(dropped)
+
+	if dropped > reported {
+		_ = c.ws.flush() // makes room for the warning, a failing output fails Sync as well
+
+		ent := zapcore.Entry{Level: zapcore.WarnLevel, Time: time.Now(), Message: "log entries dropped"}
+		_ = c.Core.Write(ent, []zapcore.Field{zap.Uint64("count", dropped-reported)})
+	}
+
+	return c.Core.Sync()
+}
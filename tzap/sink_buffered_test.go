@@ -0,0 +1,149 @@
+package tzap
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+type testSyncer struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	block chan struct{}
+}
+
+func (s *testSyncer) Write(p []byte) (int, error) {
+	if s.block != nil {
+		<-s.block
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.buf.Write(p)
+}
+
+func (*testSyncer) Sync() error { return nil }
+
+func (s *testSyncer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.buf.String()
+}
+
+func TestBufferedWriteSyncer_Sync(t *testing.T) {
+	t.Parallel()
+
+	out := &testSyncer{}
+	ws := NewBufferedWriteSyncer(out, BufferedConfig{Size: 8, FlushInterval: time.Hour})
+
+	_, err := ws.Write([]byte("abcd"))
+	require.NoError(t, err)
+	assert.Empty(t, out.String())
+
+	_, err = ws.Write([]byte("efgh"))
+	require.NoError(t, err)
+	assert.Empty(t, out.String())
+
+	_, err = ws.Write([]byte("ij"))
+	require.NoError(t, err)
+	assert.Equal(t, "abcdefgh", out.String())
+
+	_, err = ws.Write([]byte("0123456789"))
+	require.NoError(t, err)
+	assert.Equal(t, "abcdefghij0123456789", out.String())
+
+	_, err = ws.Write([]byte("kl"))
+	require.NoError(t, err)
+	require.NoError(t, ws.Stop())
+	assert.Equal(t, "abcdefghij0123456789kl", out.String())
+}
+
+func TestBufferedWriteSyncer_Drop(t *testing.T) {
+	t.Parallel()
+
+	out := &testSyncer{block: make(chan struct{})}
+	ws := NewBufferedWriteSyncer(out, BufferedConfig{Size: 4, FlushInterval: time.Hour, Async: true, Drop: true})
+
+	for range 10 {
+		_, err := ws.Write([]byte("ab"))
+		require.NoError(t, err)
+	}
+
+	assert.Positive(t, ws.Dropped())
+
+	close(out.block)
+	require.NoError(t, ws.Stop())
+	assert.Equal(t, int(10-ws.Dropped())*2, len(out.String())) //nolint:gosec // small numbers
+}
+
+func TestBufferedCore_Sync(t *testing.T) {
+	t.Parallel()
+
+	out := &testSyncer{block: make(chan struct{})}
+	ws := NewBufferedWriteSyncer(out, BufferedConfig{Size: 64, FlushInterval: time.Hour, Async: true, Drop: true})
+	cfg := DefaultStdCoreConfig(nil)
+	cfg.Output = ws
+	core := newBufferedCore(cfg.JSON(), ws)
+
+	for range 10 {
+		require.NoError(t, core.Write(zapcore.Entry{Message: "0123456789"}, nil))
+	}
+
+	close(out.block)
+	require.NoError(t, core.Sync())
+	assert.Contains(t, out.String(), `"msg":"log entries dropped","count":`)
+	require.NoError(t, ws.Stop())
+}
+
+func TestBufferedWriteSyncer_AsyncLarge(t *testing.T) {
+	t.Parallel()
+
+	out := &testSyncer{block: make(chan struct{})}
+	ws := NewBufferedWriteSyncer(out, BufferedConfig{Size: 4, FlushInterval: time.Hour, Async: true})
+
+	_, err := ws.Write([]byte("0123456789")) // the blocked output isn't written from here
+	require.NoError(t, err)
+
+	close(out.block)
+	require.NoError(t, ws.Stop())
+	assert.Equal(t, "0123456789", out.String())
+}
+
+func TestBufferedWriteSyncer_Stop(t *testing.T) {
+	t.Parallel()
+
+	out := &testSyncer{block: make(chan struct{})}
+	ws := NewBufferedWriteSyncer(out, BufferedConfig{Size: 4, FlushInterval: time.Hour, Async: true})
+
+	written := make(chan error, 1)
+
+	go func() {
+		for {
+			if _, err := ws.Write([]byte("ab")); err != nil {
+				written <- err
+				return
+			}
+		}
+	}()
+
+	stopped := make(chan error, 1)
+
+	go func() { stopped <- ws.Stop() }()
+
+	require.ErrorIs(t, <-written, ErrBufferStopped) // released while the output is still blocked
+	close(out.block)
+	require.NoError(t, <-stopped)
+
+	_, err := ws.Write([]byte("ab"))
+	require.ErrorIs(t, err, ErrBufferStopped)
+
+	_, err = ws.Write([]byte("0123456789"))
+	require.ErrorIs(t, err, ErrBufferStopped)
+}
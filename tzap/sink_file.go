@@ -0,0 +1,380 @@
+package tzap
+
+import (
+	"cmp"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	rotatingFileMode       = 0o644
+	rotatingFileDirMode    = 0o755
+	rotatingFileTimeLayout = "2006-01-02T15-04-05.000"
+	rotatingFileGzipExt    = ".gz"
+)
+
+var (
+	ErrFileClosed = errors.New("file is already closed")
+
+	_ zapcore.WriteSyncer = (*RotatingFile)(nil)
+)
+
+type RotatingFileConfig struct {
+	Path           string
+	RotateSize     int64         // rotate once the file grows beyond this many bytes, zero disables
+	RotateInterval time.Duration // rotate once the file is open for that long, zero disables
+	MaxBackups     int           // number of rotated files to keep, zero keeps all
+	Compress       bool          // gzip rotated files
+}
+
+// RotatingFile is a [zapcore.WriteSyncer] appending to a file that is rotated by size and age.
+// Rotated files are renamed to `<name>-<time><ext>`, optionally gzipped and pruned in the background.
+// Files rotated within the same millisecond get a counter too: `<name>-<time>-<n><ext>`.
+// If the rotation fails, the writes go on to the current file and the error is returned by the next Sync.
+//
+// Reopen makes it work together with external tools like logrotate:
+// the file is closed and opened again by path, so a renamed file is released.
+type RotatingFile struct {
+	cfg RotatingFileConfig
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	closed   bool
+	errs     error // of the rotations failed on write, returned by Sync
+
+	post sync.Mutex // serializes background compression and pruning
+	wg   sync.WaitGroup
+}
+
+// NewRotatingFile opens (or creates) the file at `cfg.Path` for appending.
+func NewRotatingFile(cfg RotatingFileConfig) (*RotatingFile, error) {
+	f := &RotatingFile{cfg: cfg}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return 0, ErrFileClosed
+	}
+
+	if f.needRotate(len(p)) {
+		if err := f.rotate(); err != nil {
+			f.errs = errors.Join(f.errs, fmt.Errorf("rotate: %w", err))
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	return n, err
+}
+
+func (f *RotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return ErrFileClosed
+	}
+
+	errs := f.errs
+	f.errs = nil
+
+	return errors.Join(errs, f.file.Sync())
+}
+
+// Rotate rotates the file regardless of its size and age.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return ErrFileClosed
+	}
+
+	return f.rotate()
+}
+
+// Reopen closes the file and opens it again by path.
+func (f *RotatingFile) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return ErrFileClosed
+	}
+
+	old := f.file
+
+	if err := f.open(); err != nil {
+		return err // keep writing to the old file
+	}
+
+	if err := old.Close(); err != nil {
+		return fmt.Errorf("close: %w", err)
+	}
+
+	return nil
+}
+
+// ReopenOn reopens the file each time one of the signals is received until `stop` is called.
+// Errors are reported to `onError` if it's not nil.
+func (f *RotatingFile) ReopenOn(onError func(error), signals ...os.Signal) (stop func()) {
+	notify := make(chan os.Signal, 1)
+	done := make(chan struct{})
+
+	signal.Notify(notify, signals...)
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-notify:
+			}
+
+			if err := f.Reopen(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			signal.Stop(notify)
+			close(done)
+		})
+	}
+}
+
+// Close closes the file and waits for the background compression and pruning to finish.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return ErrFileClosed
+	}
+
+	f.closed = true
+	err := f.file.Close()
+
+	f.wg.Wait()
+
+	return err
+}
+
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.cfg.Path), rotatingFileDirMode); err != nil {
+		return fmt.Errorf("mkdir: %w", err)
+	}
+
+	file, err := os.OpenFile(f.cfg.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, rotatingFileMode) //nolint:gosec // configured path
+	if err != nil {
+		return fmt.Errorf("open: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("stat: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = time.Now()
+
+	return nil
+}
+
+func (f *RotatingFile) needRotate(writeLen int) bool {
+	if f.cfg.RotateSize > 0 && f.size > 0 && f.size+int64(writeLen) > f.cfg.RotateSize {
+		return true
+	}
+
+	return f.cfg.RotateInterval > 0 && time.Since(f.openedAt) >= f.cfg.RotateInterval
+}
+
+// rotate renames the file and opens a new one, the old one is closed only then,
+// so on error the writes go on to it under the original path.
+func (f *RotatingFile) rotate() error {
+	old := f.file
+	backup := f.backupName(time.Now())
+
+	if err := os.Rename(f.cfg.Path, backup); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("rename: %w", err)
+	}
+
+	if err := f.open(); err != nil {
+		_ = os.Rename(backup, f.cfg.Path)
+		return err
+	}
+
+	_ = old.Close() // written already, nothing to do about the error
+
+	f.wg.Add(1)
+
+	go func() {
+		defer f.wg.Done()
+
+		f.post.Lock()
+		defer f.post.Unlock()
+
+		// errors are ignored: there's no place to report them to except for the log itself
+		if f.cfg.Compress {
+			_ = gzipFile(backup)
+		}
+
+		_ = f.prune()
+	}()
+
+	return nil
+}
+
+// backupName returns a name not taken by a backup, compressed or not.
+func (f *RotatingFile) backupName(t time.Time) string {
+	prefix, ext := f.backupPrefixExt()
+	name := prefix + t.Format(rotatingFileTimeLayout)
+
+	for n := 1; ; n++ {
+		if !fileExists(name+ext) && !fileExists(name+ext+rotatingFileGzipExt) {
+			return name + ext
+		}
+
+		name = prefix + t.Format(rotatingFileTimeLayout) + "-" + strconv.Itoa(n)
+	}
+}
+
+func (f *RotatingFile) backupPrefixExt() (prefix, ext string) {
+	ext = filepath.Ext(f.cfg.Path)
+	return strings.TrimSuffix(f.cfg.Path, ext) + "-", ext
+}
+
+func (f *RotatingFile) prune() error {
+	if f.cfg.MaxBackups <= 0 {
+		return nil
+	}
+
+	prefix, ext := f.backupPrefixExt()
+
+	matches, err := filepath.Glob(globEscape(prefix) + "*")
+	if err != nil {
+		return fmt.Errorf("glob: %w", err)
+	}
+
+	type backup struct {
+		name string
+		ts   string
+		n    int
+	}
+
+	backups := make([]backup, 0, len(matches))
+
+	for _, m := range matches {
+		ts := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(m, prefix), rotatingFileGzipExt), ext)
+		if ts, n, ok := parseBackupStamp(ts); ok {
+			backups = append(backups, backup{name: m, ts: ts, n: n})
+		}
+	}
+
+	if len(backups) <= f.cfg.MaxBackups {
+		return nil
+	}
+
+	// the time layout is sortable, the counter breaks the ties, newest first
+	slices.SortFunc(backups, func(a, b backup) int {
+		return cmp.Or(strings.Compare(b.ts, a.ts), cmp.Compare(b.n, a.n))
+	})
+
+	var errs error
+
+	for _, b := range backups[f.cfg.MaxBackups:] {
+		errs = errors.Join(errs, os.Remove(b.name))
+	}
+
+	return errs
+}
+
+// parseBackupStamp splits `<time>[-<n>]` of a backup name.
+func parseBackupStamp(s string) (string, int, bool) {
+	if len(s) < len(rotatingFileTimeLayout) {
+		return "", 0, false
+	}
+
+	ts, rest := s[:len(rotatingFileTimeLayout)], s[len(rotatingFileTimeLayout):]
+	if _, err := time.Parse(rotatingFileTimeLayout, ts); err != nil {
+		return "", 0, false
+	}
+
+	if rest == "" {
+		return ts, 0, true
+	}
+
+	n, err := strconv.Atoi(strings.TrimPrefix(rest, "-"))
+	if err != nil || !strings.HasPrefix(rest, "-") || n < 1 {
+		return "", 0, false
+	}
+
+	return ts, n, true
+}
+
+func fileExists(name string) bool {
+	_, err := os.Lstat(name)
+	return err == nil
+}
+
+func gzipFile(name string) error {
+	src, err := os.Open(name) //nolint:gosec // name is built from the configured path
+	if err != nil {
+		return fmt.Errorf("open: %w", err)
+	}
+	defer func() { _ = src.Close() }()
+
+	dst, err := os.OpenFile(name+rotatingFileGzipExt, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, rotatingFileMode) //nolint:gosec // configured path
+	if err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	zw := gzip.NewWriter(dst)
+
+	if _, err = io.Copy(zw, src); err != nil {
+		_ = zw.Close()
+		_ = dst.Close()
+
+		return fmt.Errorf("compress: %w", err)
+	}
+
+	if err = errors.Join(zw.Close(), dst.Close()); err != nil {
+		return fmt.Errorf("close: %w", err)
+	}
+
+	return os.Remove(name)
+}
+
+func globEscape(s string) string {
+	return strings.NewReplacer(`*`, `\*`, `?`, `\?`, `[`, `\[`, `\`, `\\`).Replace(s)
+}
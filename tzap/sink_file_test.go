@@ -0,0 +1,183 @@
+package tzap
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile_RotateSize(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	f, err := NewRotatingFile(RotatingFileConfig{Path: path, RotateSize: 10, MaxBackups: 2})
+	require.NoError(t, err)
+
+	for _, line := range []string{"0123456\n", "abcdefg\n", "ABCDEFG\n", "xyz\n"} {
+		_, err = f.Write([]byte(line))
+		require.NoError(t, err)
+
+		time.Sleep(2 * time.Millisecond) // distinct backup names
+	}
+
+	require.NoError(t, f.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "xyz\n", string(data))
+
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+
+	data, err = os.ReadFile(backups[0])
+	require.NoError(t, err)
+	assert.Equal(t, "abcdefg\n", string(data))
+
+	data, err = os.ReadFile(backups[1])
+	require.NoError(t, err)
+	assert.Equal(t, "ABCDEFG\n", string(data))
+}
+
+func TestRotatingFile_Compress(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	f, err := NewRotatingFile(RotatingFileConfig{Path: path, Compress: true})
+	require.NoError(t, err)
+
+	_, err = f.Write([]byte("foo\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Rotate())
+	require.NoError(t, f.Close())
+
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*"))
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.True(t, strings.HasSuffix(backups[0], ".log.gz"))
+}
+
+func TestRotatingFile_Reopen(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	f, err := NewRotatingFile(RotatingFileConfig{Path: path})
+	require.NoError(t, err)
+
+	_, err = f.Write([]byte("foo\n"))
+	require.NoError(t, err)
+	require.NoError(t, os.Rename(path, path+".1"))
+
+	_, err = f.Write([]byte("bar\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Reopen())
+
+	_, err = f.Write([]byte("baz\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.ErrorIs(t, f.Close(), ErrFileClosed)
+
+	data, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	assert.Equal(t, "foo\nbar\n", string(data))
+
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "baz\n", string(data))
+}
+
+func TestRotatingFile_ReopenError(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	f, err := NewRotatingFile(RotatingFileConfig{Path: path})
+	require.NoError(t, err)
+	require.NoError(t, os.Rename(path, path+".1"))
+	require.NoError(t, os.Mkdir(path, 0o700))
+
+	require.Error(t, f.Reopen())
+
+	_, err = f.Write([]byte("foo\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	data, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	assert.Equal(t, "foo\n", string(data))
+}
+
+func TestRotatingFile_RotateError(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sub", "app.log")
+
+	f, err := NewRotatingFile(RotatingFileConfig{Path: path, RotateSize: 4})
+	require.NoError(t, err)
+
+	_, err = f.Write([]byte("foo\n"))
+	require.NoError(t, err)
+
+	// the rename of the rotation fails, since the directory of the path is a file now
+	require.NoError(t, os.Rename(filepath.Join(dir, "sub"), filepath.Join(dir, "moved")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub"), nil, 0o600))
+
+	_, err = f.Write([]byte("bar\n"))
+	require.NoError(t, err)
+	require.ErrorContains(t, f.Sync(), "rotate")
+	require.NoError(t, f.Sync())
+	require.NoError(t, f.Close())
+
+	data, err := os.ReadFile(filepath.Join(dir, "moved", "app.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "foo\nbar\n", string(data))
+}
+
+func TestRotatingFile_RotateSameTime(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	f, err := NewRotatingFile(RotatingFileConfig{Path: path, MaxBackups: 2})
+	require.NoError(t, err)
+
+	now := time.Now()
+
+	for _, line := range []string{"a\n", "b\n", "c\n"} {
+		_, err = f.Write([]byte(line))
+		require.NoError(t, err)
+
+		backup := f.backupName(now)
+		require.NoError(t, os.Rename(path, backup))
+		require.NoError(t, f.Reopen())
+	}
+
+	require.NoError(t, f.prune())
+	require.NoError(t, f.Close())
+
+	stamp := now.Format(rotatingFileTimeLayout)
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		filepath.Join(dir, "app-"+stamp+"-1.log"),
+		filepath.Join(dir, "app-"+stamp+"-2.log"),
+	}, backups)
+
+	data, err := os.ReadFile(filepath.Join(dir, "app-"+stamp+"-2.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "c\n", string(data))
+}
@@ -0,0 +1,230 @@
+package tzap
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	_ slog.Handler            = (*SlogHandler)(nil)
+	_ zapcore.ObjectMarshaler = slogGroupMarshaler(nil)
+)
+
+// SlogHandler is a [slog.Handler] writing records through a zap logger.
+// Groups are mapped to zap namespaces, so the output is shaped by the same encoder as the one of the zap logger.
+type SlogHandler struct {
+	log    *zap.Logger // with the attrs out of groups
+	fields []zap.Field // attrs in groups, after the namespaces of the groups, kept apart so the context fields aren't nested
+	groups []string    // opened once there's an attr to put into them, so empty groups are omitted
+}
+
+// NewSlogHandler creates a handler that writes records with the logger, so its caller and stacktrace options apply.
+// The caller is the one of the slog call, and the trace and the request ID of the record context are added
+// like with [Context] and [WithRequestID].
+func NewSlogHandler(log *zap.Logger) *SlogHandler {
+	return &SlogHandler{log: log}
+}
+
+func (h *SlogHandler) Enabled(_ context.Context, lvl slog.Level) bool {
+	return h.log.Core().Enabled(SlogLevel(lvl))
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, rec slog.Record) error {
+	ce := h.log.Check(SlogLevel(rec.Level), rec.Message)
+	if ce == nil {
+		return nil
+	}
+
+	if !rec.Time.IsZero() {
+		ce.Time = rec.Time
+	}
+
+	if ce.Caller.Defined {
+		ce.Caller = slogCaller(rec.PC)
+	}
+
+	if ce.Stack != "" {
+		ce.Stack = trimSlogStack(ce.Stack)
+	}
+
+	fields := make([]zap.Field, 0, rec.NumAttrs())
+
+	rec.Attrs(func(attr slog.Attr) bool {
+		fields = appendSlogAttr(fields, attr)
+		return true
+	})
+
+	fields = h.nest(fields)
+	ce.Write(slices.Concat(slogContextFields(ctx), h.fields, fields)...)
+
+	return nil
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make([]zap.Field, 0, len(attrs))
+	for _, attr := range attrs {
+		fields = appendSlogAttr(fields, attr)
+	}
+
+	if len(fields) == 0 {
+		return h
+	}
+
+	if len(h.fields) == 0 && len(h.groups) == 0 {
+		return &SlogHandler{log: h.log.With(fields...)}
+	}
+
+	return &SlogHandler{
+		log:    h.log,
+		fields: slices.Concat(h.fields, h.nest(fields)),
+	}
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &SlogHandler{
+		log:    h.log,
+		fields: h.fields,
+		groups: append(slices.Clip(h.groups), name),
+	}
+}
+
+// nest puts the fields into the pending groups, if there are fields at all.
+func (h *SlogHandler) nest(fields []zap.Field) []zap.Field {
+	if len(fields) == 0 || len(h.groups) == 0 {
+		return fields
+	}
+
+	nested := make([]zap.Field, 0, len(h.groups)+len(fields))
+	for _, group := range h.groups {
+		nested = append(nested, zap.Namespace(group))
+	}
+
+	return append(nested, fields...)
+}
+
+func slogContextFields(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+
+	fields := []zap.Field{Context(ctx)}
+
+	if id := RequestID(ctx); id != "" {
+		fields = append(fields, zap.String(KeyRequestID, id))
+	}
+
+	return fields
+}
+
+func slogCaller(pc uintptr) zapcore.EntryCaller {
+	if pc == 0 {
+		return zapcore.EntryCaller{}
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	caller := zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, true)
+	caller.Function = frame.Function
+
+	return caller
+}
+
+// trimSlogStack drops the frames of the handler, slog and log from the top of the stack captured by zap.
+func trimSlogStack(stack string) string {
+	lines := strings.Split(stack, "\n")
+
+	for i := 0; i+1 < len(lines); i += 2 {
+		fn := lines[i]
+		if !strings.HasPrefix(fn, "github.com/heffcodex/the/tzap.(*SlogHandler)") &&
+			!strings.HasPrefix(fn, "log/slog.") &&
+			!strings.HasPrefix(fn, "log.") {
+			return strings.Join(lines[i:], "\n")
+		}
+	}
+
+	return stack
+}
+
+// SlogLevel maps slog levels to zap ones, levels in between go to the lower zap level.
+func SlogLevel(lvl slog.Level) zapcore.Level {
+	switch {
+	case lvl < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case lvl < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case lvl < slog.LevelError:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+func appendSlogAttr(fields []zap.Field, attr slog.Attr) []zap.Field {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return append(fields, zap.String(attr.Key, attr.Value.String()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(attr.Key, attr.Value.Int64()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(attr.Key, attr.Value.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(attr.Key, attr.Value.Float64()))
+	case slog.KindBool:
+		return append(fields, zap.Bool(attr.Key, attr.Value.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Duration(attr.Key, attr.Value.Duration()))
+	case slog.KindTime:
+		return append(fields, zap.Time(attr.Key, attr.Value.Time()))
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		if len(group) == 0 {
+			return fields
+		}
+
+		if attr.Key == "" {
+			for _, a := range group {
+				fields = appendSlogAttr(fields, a)
+			}
+
+			return fields
+		}
+
+		return append(fields, zap.Object(attr.Key, slogGroupMarshaler(group)))
+	default:
+		if err, ok := attr.Value.Any().(error); ok {
+			return append(fields, zap.NamedError(attr.Key, err))
+		}
+
+		return append(fields, zap.Any(attr.Key, attr.Value.Any()))
+	}
+}
+
+type slogGroupMarshaler []slog.Attr
+
+func (m slogGroupMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	fields := make([]zap.Field, 0, len(m))
+	for _, attr := range m {
+		fields = appendSlogAttr(fields, attr)
+	}
+
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+
+	return nil
+}
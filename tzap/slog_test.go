@@ -0,0 +1,70 @@
+package tzap
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSlogHandler(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	log := slog.New(NewSlogHandler(zap.New(core, zap.AddCaller()).Named("lib")))
+
+	log.Debug("hidden")
+	log.With("a", 1).WithGroup("g").Warn("msg", "b", true, slog.Group("h", "c", "x"), "err", errors.New("boom"))
+	log.Log(t.Context(), slog.LevelError+1, "custom")
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+	assert.Equal(t, "lib", entries[0].LoggerName)
+	assert.True(t, entries[0].Caller.Defined)
+	assert.Contains(t, entries[0].Caller.Function, "TestSlogHandler")
+	assert.Equal(t, map[string]any{
+		"a": int64(1),
+		"g": map[string]any{
+			"b":   true,
+			"h":   map[string]any{"c": "x"},
+			"err": "boom",
+		},
+	}, entries[0].ContextMap())
+
+	assert.Equal(t, zapcore.ErrorLevel, entries[1].Level)
+	assert.Equal(t, "custom", entries[1].Message)
+}
+
+func TestSlogHandler_Options(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	log := slog.New(NewSlogHandler(zap.New(NewTraceCore(core), zap.AddStacktrace(zapcore.ErrorLevel))))
+
+	ctx := WithRequestID(testSpanContext(t), "req")
+
+	log.WithGroup("empty").InfoContext(ctx, "no attrs")
+	log.WithGroup("g").With("a", 1).ErrorContext(ctx, "failed")
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+
+	assert.False(t, entries[0].Caller.Defined)
+	assert.Empty(t, entries[0].Stack)
+	assert.Equal(t, map[string]any{
+		KeyTraceID:   "0102030405060708090a0b0c0d0e0f10",
+		KeySpanID:    "0102030405060708",
+		KeyRequestID: "req",
+	}, entries[0].ContextMap())
+
+	assert.True(t, strings.HasPrefix(entries[1].Stack, "github.com/heffcodex/the/tzap.TestSlogHandler_Options"), entries[1].Stack)
+	assert.Equal(t, map[string]any{"a": int64(1)}, entries[1].ContextMap()["g"])
+}
@@ -8,19 +8,30 @@ import (
 )
 
 const (
-	KeyMessage     = "msg"
-	KeyLevel       = "level"
-	KeyTime        = "ts"
-	KeyName        = "logger"
-	KeyCaller      = "caller"
-	KeyFunction    = zapcore.OmitKey
-	KeyStacktrace  = "stacktrace"
-	KeyHTTPRequest = "http_request"
+	KeyMessage      = "msg"
+	KeyLevel        = "level"
+	KeyTime         = "ts"
+	KeyName         = "logger"
+	KeyCaller       = "caller"
+	KeyFunction     = zapcore.OmitKey
+	KeyStacktrace   = "stacktrace"
+	KeyHTTPRequest  = "http_request"
+	KeyHTTPResponse = "http_response"
+	KeyHTTPExchange = "httpRequest"
+	KeyGRPCCall     = "grpc_call"
+	KeyRequestID    = "request_id"
+	KeyMethod       = "method"
+	KeyTraceID      = "trace_id"
+	KeySpanID       = "span_id"
+	KeyError        = "error"
+	KeyRepeated     = "repeated"
+	KeyRateLimited  = "rate_limited"
 )
 
 type StdCoreConfig struct {
 	zapcore.EncoderConfig
 	LevelEnabler zapcore.LevelEnabler
+	Output       zapcore.WriteSyncer // locked os.Stderr if nil
 }
 
 func DefaultStdCoreConfig(le zapcore.LevelEnabler) *StdCoreConfig {
@@ -57,11 +68,26 @@ func (c *StdCoreConfig) JSON() zapcore.Core {
 	return c.core(enc)
 }
 
+func (c *StdCoreConfig) Logfmt() zapcore.Core {
+	enc := NewLogfmtEncoder(c.EncoderConfig)
+	return c.core(enc)
+}
+
+func (c *StdCoreConfig) Pretty() zapcore.Core {
+	enc := NewPrettyEncoder(c.EncoderConfig, true)
+	return c.core(enc)
+}
+
 func (c *StdCoreConfig) core(enc zapcore.Encoder) zapcore.Core {
 	le := c.LevelEnabler
 	if le == nil {
 		le = zap.LevelEnablerFunc(func(zapcore.Level) bool { return true })
 	}
 
-	return zapcore.NewCore(enc, zapcore.Lock(os.Stderr), le)
+	out := c.Output
+	if out == nil {
+		out = zapcore.Lock(os.Stderr)
+	}
+
+	return zapcore.NewCore(enc, out, le)
 }
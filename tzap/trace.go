@@ -0,0 +1,97 @@
+package tzap
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	traceContextKey = "_tzap_trace_ctx"
+)
+
+var (
+	_ zapcore.Core = (*traceCore)(nil)
+)
+
+// TraceFields returns the trace and span ID fields of the span held by the context,
+// or nothing if there's no valid span.
+func TraceFields(ctx context.Context) []zap.Field {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+
+	return []zap.Field{
+		zap.String(KeyTraceID, sc.TraceID().String()),
+		zap.String(KeySpanID, sc.SpanID().String()),
+	}
+}
+
+// Context returns a field carrying the context, so a core wrapped with [NewTraceCore] expands it to [TraceFields].
+// Other cores skip it.
+func Context(ctx context.Context) zap.Field {
+	return zap.Field{Key: traceContextKey, Type: zapcore.SkipType, Interface: ctx}
+}
+
+// NewTraceCore wraps the core to replace [Context] fields with [TraceFields].
+func NewTraceCore(core zapcore.Core) zapcore.Core {
+	return &traceCore{Core: core}
+}
+
+type traceCore struct {
+	zapcore.Core
+}
+
+func (c *traceCore) With(fields []zapcore.Field) zapcore.Core {
+	return &traceCore{Core: c.Core.With(expandTraceFields(fields))}
+}
+
+func (c *traceCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+func (c *traceCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, expandTraceFields(fields))
+}
+
+func expandTraceFields(fields []zapcore.Field) []zapcore.Field {
+	idx := -1
+
+	for i := range fields {
+		if isTraceContextField(fields[i]) {
+			idx = i
+			break
+		}
+	}
+
+	if idx < 0 {
+		return fields
+	}
+
+	res := make([]zapcore.Field, 0, len(fields)+1)
+	res = append(res, fields[:idx]...)
+
+	for _, f := range fields[idx:] {
+		if !isTraceContextField(f) {
+			res = append(res, f)
+			continue
+		}
+
+		if ctx, ok := f.Interface.(context.Context); ok {
+			res = append(res, TraceFields(ctx)...)
+		}
+	}
+
+	return res
+}
+
+func isTraceContextField(f zapcore.Field) bool {
+	return f.Type == zapcore.SkipType && f.Key == traceContextKey
+}
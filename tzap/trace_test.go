@@ -0,0 +1,68 @@
+package tzap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func testSpanContext(t *testing.T) context.Context {
+	t.Helper()
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SpanID:  trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+	})
+	require.True(t, sc.IsValid())
+
+	return trace.ContextWithSpanContext(context.Background(), sc)
+}
+
+func TestTraceFields(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, TraceFields(context.Background()))
+	assert.Equal(t, []zap.Field{
+		zap.String(KeyTraceID, "0102030405060708090a0b0c0d0e0f10"),
+		zap.String(KeySpanID, "0102030405060708"),
+	}, TraceFields(testSpanContext(t)))
+}
+
+func TestTraceCore(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := zap.New(NewTraceCore(core))
+	ctx := testSpanContext(t)
+
+	log.Info("with", zap.Int("a", 1), Context(ctx), zap.Int("b", 2))
+	log.Info("without", Context(context.Background()))
+	log.With(Context(ctx)).Info("logger")
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, map[string]any{
+		"a": int64(1), "b": int64(2), KeyTraceID: "0102030405060708090a0b0c0d0e0f10", KeySpanID: "0102030405060708",
+	}, entries[0].ContextMap())
+	assert.Empty(t, entries[1].ContextMap())
+	assert.Len(t, entries[2].ContextMap(), 2)
+}
+
+func TestFromContext_Trace(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	ctx := WithLogger(testSpanContext(t), zap.New(core))
+	ctx = WithContext(ctx, zap.String("a", "b"))
+
+	FromContext(ctx).Info("msg")
+	require.Equal(t, 1, logs.Len())
+	assert.Len(t, logs.All()[0].Context, 3)
+}
@@ -0,0 +1,190 @@
+// Package tzaptest provides an in-memory app logger for tests and assertions on the captured entries.
+package tzaptest
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/heffcodex/the/tzap"
+)
+
+var (
+	_ zapcore.WriteSyncer = (*lockedBuffer)(nil)
+)
+
+// Logs holds the entries captured by the logger made with [New].
+type Logs struct {
+	*observer.ObservedLogs
+	t testing.TB
+}
+
+// New returns a logger capturing the entries enabled by `le` (all if nil).
+// The entries are also encoded with [tzap.DefaultStdCoreConfig] and dumped to the test log if the test fails.
+func New(t testing.TB, le zapcore.LevelEnabler) (*zap.Logger, *Logs) {
+	t.Helper()
+
+	if le == nil {
+		le = zapcore.DebugLevel
+	}
+
+	buf := new(lockedBuffer)
+
+	cfg := tzap.DefaultStdCoreConfig(le)
+	cfg.Output = buf
+
+	core, logs := observer.New(le)
+
+	t.Cleanup(func() {
+		if t.Failed() && buf.Len() > 0 {
+			t.Logf("captured logs:\n%s", buf.String())
+		}
+	})
+
+	log := zap.New(zapcore.NewTee(core, cfg.Console()), zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
+
+	return log, &Logs{ObservedLogs: logs, t: t}
+}
+
+// Messages returns the messages of all the captured entries.
+func (l *Logs) Messages() []string {
+	entries := l.All()
+	msgs := make([]string, len(entries))
+
+	for i, e := range entries {
+		msgs[i] = e.Message
+	}
+
+	return msgs
+}
+
+// Find returns the entries with the message.
+func (l *Logs) Find(msg string) []observer.LoggedEntry {
+	return l.FilterMessage(msg).All()
+}
+
+// AssertLogged asserts that an entry with the message was captured.
+func (l *Logs) AssertLogged(msg string) bool {
+	l.t.Helper()
+	return assert.Contains(l.t, l.Messages(), msg, "no entry with the message")
+}
+
+// AssertNotLogged asserts that no entry with the message was captured.
+func (l *Logs) AssertNotLogged(msg string) bool {
+	l.t.Helper()
+	return assert.NotContains(l.t, l.Messages(), msg, "unexpected entry with the message")
+}
+
+// AssertLevel asserts that an entry with the message was captured at the level.
+func (l *Logs) AssertLevel(msg string, lvl zapcore.Level) bool {
+	l.t.Helper()
+
+	return l.assertAny(msg, func(e observer.LoggedEntry) bool {
+		return e.Level == lvl
+	}, "no entry at level %s", lvl)
+}
+
+// AssertLogger asserts that an entry with the message was captured by the named logger.
+func (l *Logs) AssertLogger(msg, name string) bool {
+	l.t.Helper()
+
+	return l.assertAny(msg, func(e observer.LoggedEntry) bool {
+		return e.LoggerName == name
+	}, "no entry of logger %q", name)
+}
+
+// AssertField asserts that an entry with the message has the field with the value.
+// The key is a dot-separated path reaching the fields of objects, e.g. `http_request.method`.
+// The value is compared with the one produced by [zapcore.MapObjectEncoder], numeric types are converted.
+func (l *Logs) AssertField(msg, key string, value any) bool {
+	l.t.Helper()
+
+	return l.assertAny(msg, func(e observer.LoggedEntry) bool {
+		v, ok := Field(e, key)
+		return ok && assert.ObjectsAreEqualValues(value, v)
+	}, "no entry with field %s=%v", key, value)
+}
+
+// AssertNoField asserts that entries with the message have no field with the key, see [Logs.AssertField].
+func (l *Logs) AssertNoField(msg, key string) bool {
+	l.t.Helper()
+
+	for _, e := range l.Find(msg) {
+		if v, ok := Field(e, key); ok {
+			return assert.Fail(l.t, "unexpected field", "%q: %s=%v", msg, key, v)
+		}
+	}
+
+	return true
+}
+
+func (l *Logs) assertAny(msg string, match func(e observer.LoggedEntry) bool, failMsg string, args ...any) bool {
+	l.t.Helper()
+
+	entries := l.Find(msg)
+	if len(entries) == 0 {
+		return assert.Fail(l.t, "no entry with the message", "%q, captured: %q", msg, l.Messages())
+	}
+
+	for _, e := range entries {
+		if match(e) {
+			return true
+		}
+	}
+
+	return assert.Failf(l.t, "entry mismatch", "%q: "+failMsg, append([]any{msg}, args...)...)
+}
+
+// Field returns the value of the entry field by a dot-separated path, see [Logs.AssertField].
+func Field(e observer.LoggedEntry, key string) (any, bool) {
+	var v any = e.ContextMap()
+
+	for part := range strings.SplitSeq(key, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		if v, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (*lockedBuffer) Sync() error {
+	return nil
+}
+
+func (b *lockedBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Len()
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
@@ -0,0 +1,38 @@
+package tzaptest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/heffcodex/the/tzap"
+)
+
+func TestLogs(t *testing.T) {
+	t.Parallel()
+
+	log, logs := New(t, zapcore.InfoLevel)
+
+	log.Debug("hidden")
+	log.Named("http").Warn("request", tzap.HTTPRequest(httptest.NewRequest(http.MethodPost, "/path", nil)), zap.Int("n", 1))
+
+	assert.Equal(t, []string{"request"}, logs.Messages())
+
+	logs.AssertLogged("request")
+	logs.AssertNotLogged("hidden")
+	logs.AssertLevel("request", zapcore.WarnLevel)
+	logs.AssertLogger("request", "http")
+	logs.AssertField("request", "n", 1)
+	logs.AssertField("request", "http_request.method", http.MethodPost)
+	logs.AssertNoField("request", "http_request.body")
+
+	mock := new(testing.T)
+	_, mockLogs := New(mock, nil)
+
+	assert.False(t, mockLogs.AssertLogged("request"))
+	assert.True(t, mock.Failed())
+}